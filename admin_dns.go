@@ -0,0 +1,344 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cast"
+)
+
+type (
+	// DNSClusterServer is a remote DirectAdmin server in the multi-server DNS cluster.
+	DNSClusterServer struct {
+		// DomainCheck makes domain creation on this server fail if the domain already exists on the remote server.
+		DomainCheck bool   `json:"domainCheck" yaml:"domainCheck"`
+		IP          string `json:"ip" yaml:"ip"`
+		// Passkey is write-only, DA never returns it.
+		Passkey  string `json:"passkey,omitempty" yaml:"passkey,omitempty"`
+		Port     int    `json:"port" yaml:"port"`
+		SSL      bool   `json:"ssl" yaml:"ssl"`
+		Username string `json:"username" yaml:"username"`
+		// ZoneTransfer sends zones to the remote server whenever they change.
+		ZoneTransfer bool `json:"zoneTransfer" yaml:"zoneTransfer"`
+	}
+
+	DNSZone struct {
+		Domain string `json:"domain" yaml:"domain"`
+		// Owner is empty for zones that don't belong to a user, e.g. zones created directly by an admin.
+		Owner string `json:"owner" yaml:"owner"`
+	}
+
+	rawDNSClusterServer struct {
+		DomainCheck  string `json:"domain_check"`
+		Port         string `json:"port"`
+		SSL          string `json:"ssl"`
+		Username     string `json:"user"`
+		ZoneTransfer string `json:"dns"`
+	}
+)
+
+// CheckDNSZoneInCluster (admin) checks whether the given zone exists on each server in the DNS cluster. The returned map
+// is keyed by server IP.
+func (c *AdminContext) CheckDNSZoneInCluster(domain string) (map[string]bool, error) {
+	var rawResults map[string]string
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_MULTI_SERVER?action=domain_check&domain="+url.QueryEscape(domain), nil, &rawResults); err != nil {
+		return nil, fmt.Errorf("failed to check zone across cluster: %w", err)
+	}
+
+	results := make(map[string]bool, len(rawResults))
+	for ip, exists := range rawResults {
+		results[ip] = parseOnOff(exists)
+	}
+
+	return results, nil
+}
+
+// CreateDNSClusterServer (admin) adds the given remote server to the DNS cluster.
+func (c *AdminContext) CreateDNSClusterServer(server DNSClusterServer) error {
+	return c.saveDNSClusterServer("add", server, "Server Added")
+}
+
+// CreateDNSZone (admin) creates a zone that doesn't belong to any user, using the server's DNS template.
+func (c *AdminContext) CreateDNSZone(domain string, ip string) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("ip", ip)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_ADMIN?action=create", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Domain Created" {
+		return fmt.Errorf("failed to create dns zone: %v", response.Result)
+	}
+
+	return nil
+}
+
+// CreateDNSZoneRecord (admin) creates the provided DNS record in any zone on the server.
+func (c *AdminContext) CreateDNSZoneRecord(domain string, dnsRecord DNSRecord) error {
+	var response apiGenericResponse
+
+	body := dnsRecord.formValues()
+	body.Set("domain", domain)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_ADMIN?action=add", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Record Added" {
+		return fmt.Errorf("failed to create dns record: %v", response.Result)
+	}
+
+	return nil
+}
+
+// DeleteDNSClusterServers (admin) removes the servers with the given IPs from the DNS cluster.
+func (c *AdminContext) DeleteDNSClusterServers(ips ...string) error {
+	var response apiGenericResponse
+
+	if len(ips) == 0 {
+		return errors.New("no server IPs provided")
+	}
+
+	body := url.Values{}
+	body.Set("delete", "yes")
+
+	for index, ip := range ips {
+		body.Set("select"+cast.ToString(index), ip)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_MULTI_SERVER?action=multiple", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Servers Deleted" {
+		return fmt.Errorf("failed to delete dns cluster servers: %v", response.Result)
+	}
+
+	return nil
+}
+
+// DeleteDNSZoneRecords (admin) deletes the specified DNS records from any zone on the server in a single call.
+func (c *AdminContext) DeleteDNSZoneRecords(domain string, dnsRecords ...DNSRecord) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("domain", domain)
+
+	setDNSRecordSelectors(body, dnsRecords)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_ADMIN?action=select&delete=yes", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Records Deleted" {
+		return fmt.Errorf("failed to delete dns records: %v", response.Result)
+	}
+
+	return nil
+}
+
+// DeleteDNSZones (admin) deletes the given zones. This only removes the zones, not any domains using them.
+func (c *AdminContext) DeleteDNSZones(domains ...string) error {
+	var response apiGenericResponse
+
+	if len(domains) == 0 {
+		return errors.New("no domains provided")
+	}
+
+	body := url.Values{}
+	body.Set("delete", "yes")
+
+	for index, domain := range domains {
+		body.Set("select"+cast.ToString(index), domain)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_ADMIN?action=select", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Domains Deleted" {
+		return fmt.Errorf("failed to delete dns zones: %v", response.Result)
+	}
+
+	return nil
+}
+
+// GetAllDNSZones (admin) returns every zone on the server, sorted by domain, including zones that belong to no user.
+func (c *AdminContext) GetAllDNSZones() ([]DNSZone, error) {
+	var rawZones map[string]string
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_DNS_ADMIN", nil, &rawZones); err != nil {
+		return nil, err
+	}
+
+	return translateDNSZones(rawZones), nil
+}
+
+// GetDNSClusterServers (admin) returns the remote servers in the DNS cluster, sorted by IP.
+func (c *AdminContext) GetDNSClusterServers() ([]DNSClusterServer, error) {
+	var rawServers map[string]rawDNSClusterServer
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_MULTI_SERVER", nil, &rawServers); err != nil {
+		return nil, err
+	}
+
+	servers := make([]DNSClusterServer, 0, len(rawServers))
+	for ip, rawServer := range rawServers {
+		servers = append(servers, rawServer.translate(ip))
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].IP < servers[j].IP
+	})
+
+	return servers, nil
+}
+
+// GetDNSTemplate (admin) returns the server's default DNS template, used when zones are created.
+func (c *AdminContext) GetDNSTemplate() (string, error) {
+	var response struct {
+		Template string `json:"template"`
+	}
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_DNS_ADMIN?action=template", nil, &response); err != nil {
+		return "", err
+	}
+
+	return response.Template, nil
+}
+
+// GetDNSZoneRecords (admin) returns the records of any zone on the server.
+func (c *AdminContext) GetDNSZoneRecords(domain string) ([]DNSRecord, error) {
+	var rawZone rawDNSZone
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_DNS_ADMIN?domain="+url.QueryEscape(domain), nil, &rawZone); err != nil {
+		return nil, err
+	}
+
+	return rawZone.translateRecords()
+}
+
+// TestDNSClusterServer (admin) checks that this server can log into the given cluster server.
+func (c *AdminContext) TestDNSClusterServer(ip string) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("ip", ip)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_MULTI_SERVER?action=test", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Connection Successful" {
+		return fmt.Errorf("failed to connect to dns cluster server %v: %v", ip, response.Result)
+	}
+
+	return nil
+}
+
+// UpdateDNSClusterServer (admin) updates the given cluster server's settings. The passkey is only changed if set.
+func (c *AdminContext) UpdateDNSClusterServer(server DNSClusterServer) error {
+	return c.saveDNSClusterServer("modify", server, "Server Saved")
+}
+
+// UpdateDNSTemplate (admin) overwrites the server's default DNS template. Existing zones aren't changed.
+func (c *AdminContext) UpdateDNSTemplate(template string) error {
+	var response apiGenericResponse
+
+	if template == "" {
+		return errors.New("no template provided")
+	}
+
+	body := url.Values{}
+	body.Set("template", template)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_ADMIN?action=template", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Template Saved" {
+		return fmt.Errorf("failed to update dns template: %v", response.Result)
+	}
+
+	return nil
+}
+
+func (c *AdminContext) saveDNSClusterServer(action string, server DNSClusterServer, expectedSuccess string) error {
+	var response apiGenericResponse
+
+	if server.IP == "" || server.Username == "" {
+		return errors.New("server IP and username are required")
+	}
+
+	if action == "add" && server.Passkey == "" {
+		return errors.New("a passkey is required when adding a server")
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_MULTI_SERVER?action="+action, server.formValues(), &response); err != nil {
+		return err
+	}
+
+	if response.Success != expectedSuccess {
+		return fmt.Errorf("failed to save dns cluster server: %v", response.Result)
+	}
+
+	return nil
+}
+
+// formValues returns the fields DA's multi-server form expects when adding or modifying the server. The port defaults to
+// 2222, and the passkey is only sent if set.
+func (s *DNSClusterServer) formValues() url.Values {
+	port := s.Port
+	if port == 0 {
+		port = 2222
+	}
+
+	body := url.Values{}
+	body.Set("dns", reverseParseYesNo(s.ZoneTransfer))
+	body.Set("domain_check", reverseParseYesNo(s.DomainCheck))
+	body.Set("ip", s.IP)
+	body.Set("port", strconv.Itoa(port))
+	body.Set("ssl", reverseParseYesNo(s.SSL))
+	body.Set("user", s.Username)
+
+	if s.Passkey != "" {
+		body.Set("passwd", s.Passkey)
+	}
+
+	return body
+}
+
+// translate returns a DNSClusterServer object for the server with the given IP.
+func (r *rawDNSClusterServer) translate(ip string) DNSClusterServer {
+	return DNSClusterServer{
+		DomainCheck:  parseOnOff(r.DomainCheck),
+		IP:           ip,
+		Port:         cast.ToInt(r.Port),
+		SSL:          parseOnOff(r.SSL),
+		Username:     r.Username,
+		ZoneTransfer: parseOnOff(r.ZoneTransfer),
+	}
+}
+
+// translateDNSZones returns DA's domain to owner map as zones sorted by domain.
+func translateDNSZones(rawZones map[string]string) []DNSZone {
+	zones := make([]DNSZone, 0, len(rawZones))
+	for domain, owner := range rawZones {
+		zones = append(zones, DNSZone{Domain: domain, Owner: owner})
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Domain < zones[j].Domain
+	})
+
+	return zones
+}
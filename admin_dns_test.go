@@ -0,0 +1,73 @@
+package directadmin
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDNSClusterServerFormValues(t *testing.T) {
+	server := DNSClusterServer{DomainCheck: true, IP: "192.0.2.20", Username: "admin", ZoneTransfer: true}
+
+	expected := url.Values{
+		"dns":          {"YES"},
+		"domain_check": {"YES"},
+		"ip":           {"192.0.2.20"},
+		"port":         {"2222"},
+		"ssl":          {"NO"},
+		"user":         {"admin"},
+	}
+
+	if body := server.formValues(); !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected %v, got %v", expected, body)
+	}
+
+	server.Passkey = "secret"
+	server.Port = 2223
+
+	body := server.formValues()
+	if body.Get("passwd") != "secret" || body.Get("port") != "2223" {
+		t.Errorf("expected the passkey and port to be sent, got %v", body)
+	}
+}
+
+func TestDNSClusterServerTranslation(t *testing.T) {
+	raw := rawDNSClusterServer{DomainCheck: "yes", Port: "2222", SSL: "no", Username: "admin", ZoneTransfer: "yes"}
+	expected := DNSClusterServer{DomainCheck: true, IP: "192.0.2.20", Port: 2222, Username: "admin", ZoneTransfer: true}
+
+	if server := raw.translate("192.0.2.20"); server != expected {
+		t.Errorf("expected %+v, got %+v", expected, server)
+	}
+}
+
+func TestTranslateDNSZones(t *testing.T) {
+	zones := translateDNSZones(map[string]string{"example.net": "", "example.com": "user1"})
+	expected := []DNSZone{{Domain: "example.com", Owner: "user1"}, {Domain: "example.net"}}
+
+	if !reflect.DeepEqual(zones, expected) {
+		t.Errorf("expected %+v, got %+v", expected, zones)
+	}
+}
+
+func TestSetDNSRecordSelectors(t *testing.T) {
+	body := url.Values{}
+	setDNSRecordSelectors(body, []DNSRecord{
+		{Name: "www", Type: "A", Value: "192.0.2.1"},
+		{Name: "example.com.", Type: "MX", Priority: 10, Value: "mail.example.com."},
+		{Name: "api", Type: "A", Value: "192.0.2.2"},
+	})
+
+	for _, key := range []string{"arecs0", "arecs1", "mxrecs0"} {
+		if body.Get(key) == "" {
+			t.Errorf("expected %v to be set, got %v", key, body)
+		}
+	}
+
+	if len(body) != 3 {
+		t.Errorf("expected 3 selectors, got %v", body)
+	}
+
+	if selector, _ := url.ParseQuery(body.Get("arecs1")); selector.Get("name") != "api" {
+		t.Errorf("expected records of the same type to be numbered in order, got %v", body)
+	}
+}
@@ -1,71 +1,210 @@
 package directadmin
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// backupsPerPage is how many backups are requested per page when listing backups.
+const backupsPerPage = 50
+
+// backupsDir is where DA stores user backups, relative to the user's home directory.
+const backupsDir = "/backups"
+
+const (
+	BackupItemAutoresponder = BackupItem("autoresponder")
+	BackupItemDatabase      = BackupItem("database")
+	BackupItemDatabaseData  = BackupItem("database_data")
+	BackupItemDomain        = BackupItem("domain")
+	BackupItemEmail         = BackupItem("email")
+	BackupItemEmailData     = BackupItem("email_data")
+	BackupItemEmailSettings = BackupItem("emailsettings")
+	BackupItemForwarder     = BackupItem("forwarder")
+	BackupItemFTP           = BackupItem("ftp")
+	BackupItemFTPSettings   = BackupItem("ftpsettings")
+	BackupItemList          = BackupItem("list")
+	BackupItemSubdomain     = BackupItem("subdomain")
+	BackupItemTrash         = BackupItem("trash")
+	BackupItemVacation      = BackupItem("vacation")
+)
+
+// AllBackupItems are every item DA can back up, in the order DA lists them.
+var AllBackupItems = []BackupItem{
+	BackupItemDomain,
+	BackupItemSubdomain,
+	BackupItemEmail,
+	BackupItemEmailData,
+	BackupItemEmailSettings,
+	BackupItemForwarder,
+	BackupItemAutoresponder,
+	BackupItemVacation,
+	BackupItemList,
+	BackupItemFTP,
+	BackupItemFTPSettings,
+	BackupItemDatabase,
+	BackupItemDatabaseData,
+	BackupItemTrash,
+}
+
+type (
+	Backup struct {
+		Created  time.Time `json:"created"`
+		Filename string    `json:"filename"`
+		// Items are the items the backup contains. It's empty if DA doesn't know, e.g. for uploaded backups.
+		Items     []BackupItem `json:"items"`
+		SizeBytes int          `json:"sizeBytes"`
+	}
+
+	BackupItem string
 )
 
-// CreateBackup (user) creates an account backup for the given domain, and the given items.
-func (c *UserContext) CreateBackup(domain string, backupItems ...string) error {
+// Validate checks that the item is one DA can back up.
+func (i BackupItem) Validate() error {
+	for _, item := range AllBackupItems {
+		if i == item {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid backup item: %q", string(i))
+}
+
+// CreateBackup (user) queues an account backup for the given domain, and the given items. The returned job finishes
+// once the backup file exists.
+func (c *UserContext) CreateBackup(domain string, backupItems ...BackupItem) (*Job, error) {
 	var response apiGenericResponse
 
+	if err := validateBackupItems(backupItems); err != nil {
+		return nil, err
+	}
+
+	existingBackups, err := c.GetBackups(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing backups: %w", err)
+	}
+
 	body := url.Values{}
 	body.Set("action", "backup")
 	body.Set("domain", domain)
 	body.Set("form_version", "4")
 
 	for index, backupItem := range backupItems {
-		body.Set(fmt.Sprintf("select%d", index), backupItem)
+		body.Set(fmt.Sprintf("select%d", index), string(backupItem))
+	}
+
+	if _, err = c.makeRequestOld(http.MethodPost, "SITE_BACKUP", body, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Success != "Backup creation added to queue" {
+		return nil, fmt.Errorf("failed to create backup: %v", response.Result)
+	}
+
+	return c.newBackupJob(domain, existingBackups), nil
+}
+
+// CreateBackupAllItems (user) wraps around CreateBackup and provides all available backup items.
+func (c *UserContext) CreateBackupAllItems(domain string) (*Job, error) {
+	return c.CreateBackup(domain, AllBackupItems...)
+}
+
+// DeleteBackups (user) deletes the given backup files for the given domain.
+func (c *UserContext) DeleteBackups(domain string, backupFilenames ...string) error {
+	var response apiGenericResponse
+
+	if len(backupFilenames) == 0 {
+		return errors.New("no backup filenames provided")
+	}
+
+	body := url.Values{}
+	body.Set("action", "delete")
+	body.Set("domain", domain)
+
+	for index, backupFilename := range backupFilenames {
+		if err := validateBackupFilename(backupFilename); err != nil {
+			return err
+		}
+
+		body.Set("select"+cast.ToString(index), backupFilename)
 	}
 
 	if _, err := c.makeRequestOld(http.MethodPost, "SITE_BACKUP", body, &response); err != nil {
 		return err
 	}
 
-	if response.Success != "Backup creation added to queue" {
-		return fmt.Errorf("failed to create backup: %v", response.Result)
+	if response.Success != "Backups Deleted" {
+		return fmt.Errorf("failed to delete backups: %v", response.Result)
 	}
 
 	return nil
 }
 
-// CreateBackupAllItems (user) wraps around CreateBackup and provides all available backup items.
-func (c *UserContext) CreateBackupAllItems(domain string) error {
-	return c.CreateBackup(
-		domain,
-		"domain",
-		"subdomain",
-		"email",
-		"email_data",
-		"emailsettings",
-		"forwarder",
-		"autoresponder",
-		"vacation",
-		"list",
-		"ftp",
-		"ftpsettings",
-		"database",
-		"database_data",
-		"trash",
-	)
+// DownloadBackup (user) streams the given backup file to the writer, without holding it in memory.
+func (c *UserContext) DownloadBackup(backupFilename string, writer io.Writer) error {
+	if err := validateBackupFilename(backupFilename); err != nil {
+		return err
+	}
+
+	if _, err := c.DownloadFileTo(backupsDir+"/"+backupFilename, writer, nil); err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+
+	return nil
 }
 
-// GetBackups (user) returns an array of the session user's backups for the given domain.
-func (c *UserContext) GetBackups(domain string) ([]string, error) {
-	var backups []string
+// GetBackups (user) returns the session user's backups for the given domain, newest first. DA versions that only list
+// filenames leave the other fields empty.
+func (c *UserContext) GetBackups(domain string) ([]Backup, error) {
+	backups := []Backup{}
 
-	if _, err := c.makeRequestOld(http.MethodGet, "SITE_BACKUP?domain="+domain+"&ipp=50", nil, &backups); err != nil {
-		return nil, err
+	for page := 1; ; page++ {
+		var rawBackupsPage rawBackupList
+
+		endpoint := "SITE_BACKUP?domain=" + domain + "&ipp=" + strconv.Itoa(backupsPerPage) + "&page=" + strconv.Itoa(page)
+		if _, err := c.makeRequestOld(http.MethodGet, endpoint, nil, &rawBackupsPage); err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, rawBackupsPage.translate()...)
+
+		if len(rawBackupsPage.Backups) == 0 || page >= cast.ToInt(rawBackupsPage.Info.TotalPages) {
+			break
+		}
 	}
 
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].Created.Equal(backups[j].Created) {
+			return backups[i].Created.After(backups[j].Created)
+		}
+
+		return backups[i].Filename < backups[j].Filename
+	})
+
 	return backups, nil
 }
 
-// RestoreBackup (user) restores an account backup for the given domain, and the given items.
-func (c *UserContext) RestoreBackup(domain string, backupFilename string, backupItems ...string) error {
+// RestoreBackup (user) queues a restore of an account backup for the given domain, and the given items. The returned
+// job finishes once DA sends its restore notification.
+func (c *UserContext) RestoreBackup(domain string, backupFilename string, backupItems ...BackupItem) (*Job, error) {
 	var response apiGenericResponse
 
+	if err := validateBackupFilename(backupFilename); err != nil {
+		return nil, err
+	}
+
+	if err := validateBackupItems(backupItems); err != nil {
+		return nil, err
+	}
+
 	body := url.Values{}
 	body.Set("action", "restore")
 	body.Set("domain", domain)
@@ -73,38 +212,58 @@ func (c *UserContext) RestoreBackup(domain string, backupFilename string, backup
 	body.Set("form_version", "3")
 
 	for index, backupItem := range backupItems {
-		body.Set(fmt.Sprintf("select%d", index), backupItem)
+		body.Set(fmt.Sprintf("select%d", index), string(backupItem))
 	}
 
 	if _, err := c.makeRequestOld(http.MethodPost, "SITE_BACKUP", body, &response); err != nil {
-		return err
+		return nil, err
 	}
 
 	if response.Success != "Restore will run in the background" {
-		return fmt.Errorf("failed to restore backup: %v", response.Result)
+		return nil, fmt.Errorf("failed to restore backup: %v", response.Result)
 	}
 
-	return nil
+	return c.newRestoreJob(domain), nil
 }
 
 // RestoreBackupAllItems (user) wraps around RestoreBackup and provides all available backup items.
-func (c *UserContext) RestoreBackupAllItems(domain string, backupFilename string) error {
-	return c.RestoreBackup(
-		domain,
-		backupFilename,
-		"domain",
-		"subdomain",
-		"email",
-		"email_data",
-		"emailsettings",
-		"forwarder",
-		"autoresponder",
-		"vacation",
-		"list",
-		"ftp",
-		"ftpsettings",
-		"database",
-		"database_data",
-		"trash",
-	)
+func (c *UserContext) RestoreBackupAllItems(domain string, backupFilename string) (*Job, error) {
+	return c.RestoreBackup(domain, backupFilename, AllBackupItems...)
+}
+
+// UploadBackup (user) streams a backup file from the reader into the session user's backups directory, so it can be
+// restored with RestoreBackup. An existing backup with the same filename is overwritten.
+func (c *UserContext) UploadBackup(backupFilename string, reader io.Reader) error {
+	if err := validateBackupFilename(backupFilename); err != nil {
+		return err
+	}
+
+	if err := c.UploadFileFrom(backupsDir+"/"+backupFilename, reader, -1, true, nil); err != nil {
+		return fmt.Errorf("failed to upload backup: %w", err)
+	}
+
+	return nil
+}
+
+// validateBackupFilename rejects filenames that could point outside the backups directory.
+func validateBackupFilename(backupFilename string) error {
+	if backupFilename == "" || backupFilename == "." || backupFilename == ".." || strings.ContainsAny(backupFilename, "/\\") {
+		return fmt.Errorf("invalid backup filename: %q", backupFilename)
+	}
+
+	return nil
+}
+
+func validateBackupItems(backupItems []BackupItem) error {
+	if len(backupItems) == 0 {
+		return errors.New("no backup items provided")
+	}
+
+	for _, backupItem := range backupItems {
+		if err := backupItem.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,77 @@
+package directadmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+type (
+	rawBackup struct {
+		Created  string `json:"date"`
+		Filename string `json:"file"`
+		Items    string `json:"items"`
+		Size     string `json:"size"`
+	}
+
+	rawBackupList struct {
+		Backups map[string]rawBackup `json:"backups"`
+		Info    struct {
+			TotalPages string `json:"total_pages"`
+		} `json:"info"`
+	}
+)
+
+// UnmarshalJSON accepts both the paged object with backup details and the plain list of filenames DA returns for the
+// same endpoint. The plain list has no page info, so it's treated as a single page.
+func (r *rawBackupList) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var filenames []string
+		if err := json.Unmarshal(trimmed, &filenames); err != nil {
+			return err
+		}
+
+		r.Backups = make(map[string]rawBackup, len(filenames))
+		for index, filename := range filenames {
+			r.Backups[strconv.Itoa(index)] = rawBackup{Filename: filename}
+		}
+
+		return nil
+	}
+
+	// A separate type avoids recursing back into this method.
+	type rawBackupListObject rawBackupList
+
+	return json.Unmarshal(data, (*rawBackupListObject)(r))
+}
+
+// translate returns the page's backups as Backup objects.
+func (r *rawBackupList) translate() []Backup {
+	backups := make([]Backup, 0, len(r.Backups))
+
+	for _, rawBackupData := range r.Backups {
+		backup := Backup{
+			Filename:  rawBackupData.Filename,
+			Items:     []BackupItem{},
+			SizeBytes: cast.ToInt(rawBackupData.Size),
+		}
+
+		if created := cast.ToInt64(rawBackupData.Created); created > 0 {
+			backup.Created = time.Unix(created, 0)
+		}
+
+		for _, item := range strings.Split(rawBackupData.Items, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				backup.Items = append(backup.Items, BackupItem(item))
+			}
+		}
+
+		backups = append(backups, backup)
+	}
+
+	return backups
+}
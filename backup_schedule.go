@@ -0,0 +1,350 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	BackupAppendDate       = BackupAppend("date")
+	BackupAppendDayOfMonth = BackupAppend("dayofmonth")
+	BackupAppendDayOfWeek  = BackupAppend("dayofweek")
+	BackupAppendMonth      = BackupAppend("month")
+	BackupAppendNone       = BackupAppend("nothing")
+	BackupAppendWeek       = BackupAppend("week")
+
+	BackupDestinationFTP   = BackupDestinationType("ftp")
+	BackupDestinationFTPS  = BackupDestinationType("ftps")
+	BackupDestinationLocal = BackupDestinationType("local")
+	BackupDestinationSFTP  = BackupDestinationType("sftp")
+
+	BackupUsersAll      = BackupUserSelection("all")
+	BackupUsersExcept   = BackupUserSelection("except")
+	BackupUsersSelected = BackupUserSelection("selected")
+)
+
+type (
+	// BackupAppend is the date-based subdirectory appended to the destination path, so older backups aren't
+	// overwritten.
+	BackupAppend string
+
+	BackupDestinationType string
+
+	BackupHistoryEntry struct {
+		Finished time.Time `json:"finished"`
+		Message  string    `json:"message"`
+		// ScheduleID is the scheduled backup that ran, or 0 for backups that weren't scheduled.
+		ScheduleID int       `json:"scheduleID"`
+		Started    time.Time `json:"started"`
+		Success    bool      `json:"success"`
+	}
+
+	BackupUserSelection string
+
+	ScheduledBackup struct {
+		AppendToPath BackupAppend               `json:"appendToPath" yaml:"appendToPath"`
+		Destination  ScheduledBackupDestination `json:"destination" yaml:"destination"`
+		ID           int                        `json:"id" yaml:"-"`
+		// Items are what each user's backup contains. All items are backed up if empty.
+		Items []BackupItem `json:"items" yaml:"items"`
+		// Schedule is a five-field cron expression, e.g. "0 3 * * *" for 3am daily.
+		Schedule      string              `json:"schedule" yaml:"schedule"`
+		UserSelection BackupUserSelection `json:"userSelection" yaml:"userSelection"`
+		// Users are the users included or excluded, depending on UserSelection. It's ignored when backing up all users.
+		Users []string `json:"users" yaml:"users"`
+	}
+
+	ScheduledBackupDestination struct {
+		Host string `json:"host,omitempty" yaml:"host,omitempty"`
+		// Password is write-only, DA never returns it. When updating a scheduled backup, leave it empty to keep the
+		// current password.
+		Password string `json:"password,omitempty" yaml:"password,omitempty"`
+		// Path is the local or remote directory the backups are written to.
+		Path     string                `json:"path" yaml:"path"`
+		Port     int                   `json:"port,omitempty" yaml:"port,omitempty"`
+		Type     BackupDestinationType `json:"type" yaml:"type"`
+		Username string                `json:"username,omitempty" yaml:"username,omitempty"`
+	}
+)
+
+// Validate checks the scheduled backup locally.
+func (b *ScheduledBackup) Validate() error {
+	if err := validateCronSchedule(b.Schedule); err != nil {
+		return err
+	}
+
+	switch b.UserSelection {
+	case BackupUsersAll:
+	case BackupUsersExcept, BackupUsersSelected:
+		if len(b.Users) == 0 {
+			return fmt.Errorf("at least one user is required when the user selection is %q", b.UserSelection)
+		}
+	default:
+		return fmt.Errorf("invalid user selection: %q", b.UserSelection)
+	}
+
+	switch b.AppendToPath {
+	case "", BackupAppendDate, BackupAppendDayOfMonth, BackupAppendDayOfWeek, BackupAppendMonth, BackupAppendNone, BackupAppendWeek:
+	default:
+		return fmt.Errorf("invalid append to path option: %q", b.AppendToPath)
+	}
+
+	for _, item := range b.Items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return b.Destination.Validate()
+}
+
+// Validate checks the destination locally.
+func (d *ScheduledBackupDestination) Validate() error {
+	if !strings.HasPrefix(d.Path, "/") {
+		return fmt.Errorf("destination path must be absolute: %q", d.Path)
+	}
+
+	switch d.Type {
+	case BackupDestinationLocal:
+		return nil
+	case BackupDestinationFTP, BackupDestinationFTPS, BackupDestinationSFTP:
+		if d.Host == "" || d.Username == "" {
+			return errors.New("remote destinations require a host and username")
+		}
+
+		if d.Port < 0 || d.Port > 65535 {
+			return fmt.Errorf("invalid destination port: %d", d.Port)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("invalid destination type: %q", d.Type)
+}
+
+// CreateScheduledBackup (reseller) creates a scheduled backup of the session user's users.
+func (c *ResellerContext) CreateScheduledBackup(backup ScheduledBackup) error {
+	return c.saveScheduledBackup("API_RESELLER_BACKUP", "create", backup)
+}
+
+// DeleteScheduledBackups (reseller) deletes the scheduled backups with the given IDs.
+func (c *ResellerContext) DeleteScheduledBackups(ids ...int) error {
+	return c.deleteScheduledBackups("API_RESELLER_BACKUP", ids...)
+}
+
+// GetBackupHistory (reseller) returns the session user's recent backup runs, newest first.
+func (c *ResellerContext) GetBackupHistory() ([]BackupHistoryEntry, error) {
+	return c.getBackupHistory("API_RESELLER_BACKUP")
+}
+
+// GetScheduledBackup (reseller) returns the scheduled backup with the given ID.
+func (c *ResellerContext) GetScheduledBackup(id int) (*ScheduledBackup, error) {
+	return c.getScheduledBackup("API_RESELLER_BACKUP", id)
+}
+
+// GetScheduledBackups (reseller) returns the session user's scheduled backups, sorted by ID.
+func (c *ResellerContext) GetScheduledBackups() ([]ScheduledBackup, error) {
+	return c.getScheduledBackups("API_RESELLER_BACKUP")
+}
+
+// RunScheduledBackupNow (reseller) runs the given scheduled backup immediately, in the background.
+func (c *ResellerContext) RunScheduledBackupNow(id int) error {
+	return c.runScheduledBackupNow("API_RESELLER_BACKUP", id)
+}
+
+// UpdateScheduledBackup (reseller) overwrites the scheduled backup with the same ID.
+func (c *ResellerContext) UpdateScheduledBackup(backup ScheduledBackup) error {
+	return c.saveScheduledBackup("API_RESELLER_BACKUP", "modify", backup)
+}
+
+// CreateScheduledBackup (admin) creates a scheduled backup of any users on the server.
+func (c *AdminContext) CreateScheduledBackup(backup ScheduledBackup) error {
+	return c.saveScheduledBackup("API_ADMIN_BACKUP", "create", backup)
+}
+
+// DeleteScheduledBackups (admin) deletes the admin-level scheduled backups with the given IDs.
+func (c *AdminContext) DeleteScheduledBackups(ids ...int) error {
+	return c.deleteScheduledBackups("API_ADMIN_BACKUP", ids...)
+}
+
+// GetBackupHistory (admin) returns the server's recent admin-level backup runs, newest first.
+func (c *AdminContext) GetBackupHistory() ([]BackupHistoryEntry, error) {
+	return c.getBackupHistory("API_ADMIN_BACKUP")
+}
+
+// GetScheduledBackup (admin) returns the admin-level scheduled backup with the given ID.
+func (c *AdminContext) GetScheduledBackup(id int) (*ScheduledBackup, error) {
+	return c.getScheduledBackup("API_ADMIN_BACKUP", id)
+}
+
+// GetScheduledBackups (admin) returns the server's admin-level scheduled backups, sorted by ID.
+func (c *AdminContext) GetScheduledBackups() ([]ScheduledBackup, error) {
+	return c.getScheduledBackups("API_ADMIN_BACKUP")
+}
+
+// RunScheduledBackupNow (admin) runs the given admin-level scheduled backup immediately, in the background.
+func (c *AdminContext) RunScheduledBackupNow(id int) error {
+	return c.runScheduledBackupNow("API_ADMIN_BACKUP", id)
+}
+
+// UpdateScheduledBackup (admin) overwrites the admin-level scheduled backup with the same ID.
+func (c *AdminContext) UpdateScheduledBackup(backup ScheduledBackup) error {
+	return c.saveScheduledBackup("API_ADMIN_BACKUP", "modify", backup)
+}
+
+func (c *ResellerContext) deleteScheduledBackups(endpoint string, ids ...int) error {
+	var response apiGenericResponse
+
+	if len(ids) == 0 {
+		return errors.New("no scheduled backup IDs provided")
+	}
+
+	body := url.Values{}
+	body.Set("action", "delete")
+
+	for index, id := range ids {
+		body.Set("select"+cast.ToString(index), strconv.Itoa(id))
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, endpoint, body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Backup Crons Deleted" {
+		return fmt.Errorf("failed to delete scheduled backups: %v", response.Result)
+	}
+
+	return nil
+}
+
+func (c *ResellerContext) getBackupHistory(endpoint string) ([]BackupHistoryEntry, error) {
+	var rawHistory map[string]rawBackupHistoryEntry
+
+	if _, err := c.makeRequestOld(http.MethodGet, endpoint+"?action=history", nil, &rawHistory); err != nil {
+		return nil, err
+	}
+
+	history := make([]BackupHistoryEntry, 0, len(rawHistory))
+	for _, rawEntry := range rawHistory {
+		history = append(history, rawEntry.translate())
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Started.After(history[j].Started)
+	})
+
+	return history, nil
+}
+
+func (c *ResellerContext) getScheduledBackup(endpoint string, id int) (*ScheduledBackup, error) {
+	backups, err := c.getScheduledBackups(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backup := range backups {
+		if backup.ID == id {
+			return &backup, nil
+		}
+	}
+
+	return nil, fmt.Errorf("scheduled backup not found: %d", id)
+}
+
+func (c *ResellerContext) getScheduledBackups(endpoint string) ([]ScheduledBackup, error) {
+	var rawBackups struct {
+		Crons map[string]rawScheduledBackup `json:"crons"`
+	}
+
+	if _, err := c.makeRequestOld(http.MethodGet, endpoint, nil, &rawBackups); err != nil {
+		return nil, err
+	}
+
+	backups := make([]ScheduledBackup, 0, len(rawBackups.Crons))
+	for id, rawBackup := range rawBackups.Crons {
+		backups = append(backups, rawBackup.translate(cast.ToInt(id)))
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ID < backups[j].ID
+	})
+
+	return backups, nil
+}
+
+func (c *ResellerContext) runScheduledBackupNow(endpoint string, id int) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("action", "run")
+	body.Set("id", strconv.Itoa(id))
+
+	if _, err := c.makeRequestOld(http.MethodPost, endpoint, body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Backup Added to Queue" {
+		return fmt.Errorf("failed to run scheduled backup: %v", response.Result)
+	}
+
+	return nil
+}
+
+func (c *ResellerContext) saveScheduledBackup(endpoint string, action string, backup ScheduledBackup) error {
+	var response apiGenericResponse
+
+	if err := backup.Validate(); err != nil {
+		return err
+	}
+
+	if action == "create" && backup.Destination.Type != BackupDestinationLocal && backup.Destination.Password == "" {
+		return errors.New("a password is required for remote destinations")
+	}
+
+	if action == "modify" && backup.ID == 0 {
+		return errors.New("no scheduled backup ID provided")
+	}
+
+	rawBackup := backup.translate()
+
+	body := rawBackup.formValues()
+	body.Set("action", action)
+
+	if action == "modify" {
+		body.Set("id", strconv.Itoa(backup.ID))
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, endpoint, body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Backup Cron Saved" {
+		return fmt.Errorf("failed to save scheduled backup: %v", response.Result)
+	}
+
+	return nil
+}
+
+// validateCronSchedule checks that the schedule has five fields using only the characters cron accepts.
+func validateCronSchedule(schedule string) error {
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return fmt.Errorf("schedule must have five fields (minute hour day-of-month month day-of-week): %q", schedule)
+	}
+
+	for _, field := range fields {
+		if strings.Trim(field, "0123456789*,-/") != "" {
+			return fmt.Errorf("invalid schedule field: %q", field)
+		}
+	}
+
+	return nil
+}
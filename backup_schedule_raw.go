@@ -0,0 +1,214 @@
+package directadmin
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+type (
+	rawBackupHistoryEntry struct {
+		End        string `json:"end"`
+		Message    string `json:"message"`
+		ScheduleID string `json:"id"`
+		Start      string `json:"start"`
+		Status     string `json:"status"`
+	}
+
+	rawScheduledBackup struct {
+		AppendToPath string `json:"append_to_path"`
+		DayOfMonth   string `json:"dayofmonth"`
+		DayOfWeek    string `json:"dayofweek"`
+		FTPHost      string `json:"ftp_ip"`
+		FTPPassword  string `json:"ftp_password"`
+		FTPPath      string `json:"ftp_path"`
+		FTPPort      string `json:"ftp_port"`
+		FTPSecure    string `json:"ftp_secure"`
+		FTPUsername  string `json:"ftp_username"`
+		Hour         string `json:"hour"`
+		Items        string `json:"option"`
+		LocalPath    string `json:"local_path"`
+		Minute       string `json:"minute"`
+		Month        string `json:"month"`
+		Users        string `json:"select"`
+		What         string `json:"what"`
+		Where        string `json:"where"`
+		Who          string `json:"who"`
+	}
+)
+
+func (r *rawBackupHistoryEntry) translate() BackupHistoryEntry {
+	entry := BackupHistoryEntry{
+		Message:    r.Message,
+		ScheduleID: cast.ToInt(r.ScheduleID),
+		Success:    strings.EqualFold(r.Status, "success"),
+	}
+
+	if start := cast.ToInt64(r.Start); start > 0 {
+		entry.Started = time.Unix(start, 0)
+	}
+
+	if end := cast.ToInt64(r.End); end > 0 {
+		entry.Finished = time.Unix(end, 0)
+	}
+
+	return entry
+}
+
+// formValues returns the raw scheduled backup as DA's form fields, with users and items as numbered selections.
+func (r *rawScheduledBackup) formValues() url.Values {
+	body := url.Values{}
+	body.Set("append_to_path", r.AppendToPath)
+	body.Set("dayofmonth", r.DayOfMonth)
+	body.Set("dayofweek", r.DayOfWeek)
+	body.Set("hour", r.Hour)
+	body.Set("minute", r.Minute)
+	body.Set("month", r.Month)
+	body.Set("what", r.What)
+	body.Set("when", "cron")
+	body.Set("where", r.Where)
+	body.Set("who", r.Who)
+
+	if r.Where == "local" {
+		body.Set("local_path", r.LocalPath)
+	} else {
+		body.Set("ftp_ip", r.FTPHost)
+		body.Set("ftp_path", r.FTPPath)
+		body.Set("ftp_port", r.FTPPort)
+		body.Set("ftp_secure", r.FTPSecure)
+		body.Set("ftp_username", r.FTPUsername)
+
+		if r.FTPPassword != "" {
+			body.Set("ftp_password", r.FTPPassword)
+		}
+	}
+
+	for index, user := range splitCommaList(r.Users) {
+		body.Set("select"+strconv.Itoa(index), user)
+	}
+
+	for index, item := range splitCommaList(r.Items) {
+		body.Set("option"+strconv.Itoa(index), item)
+	}
+
+	return body
+}
+
+func (r *rawScheduledBackup) translate(id int) ScheduledBackup {
+	backup := ScheduledBackup{
+		AppendToPath:  BackupAppend(r.AppendToPath),
+		ID:            id,
+		Items:         []BackupItem{},
+		Schedule:      strings.Join([]string{r.Minute, r.Hour, r.DayOfMonth, r.Month, r.DayOfWeek}, " "),
+		UserSelection: BackupUserSelection(r.Who),
+		Users:         splitCommaList(r.Users),
+	}
+
+	if r.What != "all" {
+		for _, item := range splitCommaList(r.Items) {
+			backup.Items = append(backup.Items, BackupItem(item))
+		}
+	}
+
+	if r.Where == "local" {
+		backup.Destination = ScheduledBackupDestination{
+			Path: r.LocalPath,
+			Type: BackupDestinationLocal,
+		}
+
+		return backup
+	}
+
+	backup.Destination = ScheduledBackupDestination{
+		Host:     r.FTPHost,
+		Path:     r.FTPPath,
+		Port:     cast.ToInt(r.FTPPort),
+		Type:     BackupDestinationFTP,
+		Username: r.FTPUsername,
+	}
+
+	switch r.FTPSecure {
+	case "ftps":
+		backup.Destination.Type = BackupDestinationFTPS
+	case "ssh":
+		backup.Destination.Type = BackupDestinationSFTP
+	}
+
+	return backup
+}
+
+func (b *ScheduledBackup) translate() rawScheduledBackup {
+	// Validate ensures the schedule has five fields.
+	schedule := strings.Fields(b.Schedule)
+
+	items := make([]string, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, string(item))
+	}
+
+	rawBackup := rawScheduledBackup{
+		AppendToPath: string(b.AppendToPath),
+		DayOfMonth:   schedule[2],
+		DayOfWeek:    schedule[4],
+		Hour:         schedule[1],
+		Items:        strings.Join(items, ","),
+		Minute:       schedule[0],
+		Month:        schedule[3],
+		What:         "select",
+		Where:        "ftp",
+		Who:          string(b.UserSelection),
+	}
+
+	if rawBackup.AppendToPath == "" {
+		rawBackup.AppendToPath = string(BackupAppendNone)
+	}
+
+	if len(b.Items) == 0 {
+		rawBackup.What = "all"
+	}
+
+	if b.UserSelection != BackupUsersAll {
+		rawBackup.Users = strings.Join(b.Users, ",")
+	}
+
+	switch b.Destination.Type {
+	case BackupDestinationLocal:
+		rawBackup.LocalPath = b.Destination.Path
+		rawBackup.Where = "local"
+
+		return rawBackup
+	case BackupDestinationFTPS:
+		rawBackup.FTPSecure = "ftps"
+	case BackupDestinationSFTP:
+		rawBackup.FTPSecure = "ssh"
+	default:
+		rawBackup.FTPSecure = "no"
+	}
+
+	rawBackup.FTPHost = b.Destination.Host
+	rawBackup.FTPPassword = b.Destination.Password
+	rawBackup.FTPPath = b.Destination.Path
+	rawBackup.FTPUsername = b.Destination.Username
+
+	if b.Destination.Port != 0 {
+		rawBackup.FTPPort = strconv.Itoa(b.Destination.Port)
+	}
+
+	return rawBackup
+}
+
+// splitCommaList splits a comma-separated list, skipping empty entries.
+func splitCommaList(list string) []string {
+	values := []string{}
+
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,58 @@
+package directadmin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScheduledBackupTranslation(t *testing.T) {
+	backups := []ScheduledBackup{
+		{
+			AppendToPath: BackupAppendDayOfWeek,
+			Destination: ScheduledBackupDestination{
+				Host:     "backup.example.net",
+				Path:     "/srv/backups",
+				Port:     22,
+				Type:     BackupDestinationSFTP,
+				Username: "backups",
+			},
+			ID:            3,
+			Items:         []BackupItem{BackupItemDomain, BackupItemDatabaseData},
+			Schedule:      "30 2 * * 1-5",
+			UserSelection: BackupUsersExcept,
+			Users:         []string{"alice", "bob"},
+		},
+		{
+			AppendToPath:  BackupAppendNone,
+			Destination:   ScheduledBackupDestination{Path: "/home/admin/admin_backups", Type: BackupDestinationLocal},
+			ID:            4,
+			Items:         []BackupItem{},
+			Schedule:      "0 3 1 * *",
+			UserSelection: BackupUsersAll,
+			Users:         []string{},
+		},
+	}
+
+	for _, backup := range backups {
+		if err := backup.Validate(); err != nil {
+			t.Fatalf("%d: %v", backup.ID, err)
+		}
+
+		rawBackup := backup.translate()
+		if translated := rawBackup.translate(backup.ID); !reflect.DeepEqual(translated, backup) {
+			t.Errorf("%d: expected %+v, got %+v", backup.ID, backup, translated)
+		}
+	}
+
+	body := backups[0].translate()
+	if values := body.formValues(); values.Get("select1") != "bob" || values.Get("option1") != "database_data" || values.Get("ftp_secure") != "ssh" {
+		t.Errorf("unexpected form values: %v", values)
+	}
+
+	invalid := backups[1]
+	invalid.Schedule = "daily"
+
+	if err := invalid.Validate(); err == nil {
+		t.Error("expected an invalid schedule to fail validation")
+	}
+}
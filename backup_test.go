@@ -0,0 +1,102 @@
+package directadmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBackupListTranslation(t *testing.T) {
+	const daBackups = `{"backups":{"0":{"file":"user.admin.example.tar.zst","size":"1048576","date":"1767225600","items":"domain,email,database"},"1":{"file":"uploaded.tar.gz","size":"42","date":"","items":""}},"info":{"total_pages":"3"}}`
+
+	var rawBackups rawBackupList
+	if err := json.Unmarshal([]byte(daBackups), &rawBackups); err != nil {
+		t.Fatal(err)
+	}
+
+	backups := rawBackups.translate()
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %d", len(backups))
+	}
+
+	for _, backup := range backups {
+		switch backup.Filename {
+		case "user.admin.example.tar.zst":
+			expected := Backup{
+				Created:   time.Unix(1767225600, 0),
+				Filename:  "user.admin.example.tar.zst",
+				Items:     []BackupItem{BackupItemDomain, BackupItemEmail, BackupItemDatabase},
+				SizeBytes: 1048576,
+			}
+
+			if !reflect.DeepEqual(backup, expected) {
+				t.Errorf("expected %+v, got %+v", expected, backup)
+			}
+		case "uploaded.tar.gz":
+			if !backup.Created.IsZero() || len(backup.Items) != 0 || backup.SizeBytes != 42 {
+				t.Errorf("unexpected uploaded backup: %+v", backup)
+			}
+		default:
+			t.Errorf("unexpected backup: %+v", backup)
+		}
+	}
+
+	if err := validateBackupItems([]BackupItem{BackupItemEmail, "emails"}); err == nil {
+		t.Error("expected an invalid backup item to fail validation")
+	}
+
+	if err := validateBackupFilename("../.ssh/authorized_keys"); err == nil {
+		t.Error("expected a path to fail validation")
+	}
+}
+
+func TestGetBackupsResponseShapes(t *testing.T) {
+	for _, responseFile := range []string{"testdata/site_backup_list.json", "testdata/site_backup_paged.json"} {
+		response, err := os.ReadFile(responseFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		requests := 0
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			_, _ = w.Write(response)
+		}))
+
+		api, err := New(server.URL, time.Minute, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := &UserContext{api: api, cookieJar: jar, credentials: credentials{username: "user", passkey: "passkey"}}
+
+		backups, err := c.GetBackups("example.com")
+
+		server.Close()
+
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", responseFile, err)
+			continue
+		}
+
+		filenames := make(map[string]bool, len(backups))
+		for _, backup := range backups {
+			filenames[backup.Filename] = true
+		}
+
+		if requests != 1 || len(backups) != 2 || !filenames["user.admin.example.tar.zst"] || !filenames["uploaded.tar.gz"] {
+			t.Errorf("%v: unexpected backups after %d requests: %+v", responseFile, requests, backups)
+		}
+	}
+}
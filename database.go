@@ -2,8 +2,9 @@ package directadmin
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"mime/multipart"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -105,17 +106,9 @@ func (c *UserContext) DeleteDatabase(databaseName string) error {
 // The method appends the username and ensures the file uses a valid DatabaseFormat (gz or sql).
 // Returns an error if the format is invalid or the download request fails.
 func (c *UserContext) DownloadDatabase(name string, format DatabaseFormat) ([]byte, error) {
-	name = name + "." + string(format)
-
-	if !strings.Contains(name, c.GetMyUsername()+"_") {
-		name = c.GetMyUsername() + "_" + name
-	}
-
-	switch format {
-	case DatabaseFormatGZ, DatabaseFormatSQL:
-		break
-	default:
-		return nil, fmt.Errorf("invalid database format: %v", format)
+	name, err := c.databaseDownloadName(name, format)
+	if err != nil {
+		return nil, err
 	}
 
 	response, err := c.makeRequestOld(http.MethodPost, "DB/"+name, nil, nil)
@@ -126,9 +119,27 @@ func (c *UserContext) DownloadDatabase(name string, format DatabaseFormat) ([]by
 	return response, nil
 }
 
+// DownloadDatabaseTo (user) is like DownloadDatabase, but streams the database to the writer without holding it in
+// memory, returning the number of bytes written. The progress function is optional.
+func (c *UserContext) DownloadDatabaseTo(name string, format DatabaseFormat, writer io.Writer, progress ProgressFunc) (int64, error) {
+	name, err := c.databaseDownloadName(name, format)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := c.makeRequestOldStream(http.MethodPost, "DB/"+name, nil, writer, progress)
+	if err != nil {
+		return written, fmt.Errorf("failed to download database: %w", err)
+	}
+
+	return written, nil
+}
+
+// DownloadDatabaseToDisk (user) wraps DownloadDatabaseTo and writes the output to the given path.
 func (c *UserContext) DownloadDatabaseToDisk(name string, format DatabaseFormat, outputPath string) error {
-	return writeToDisk(outputPath, func() ([]byte, error) {
-		return c.DownloadDatabase(name, format)
+	return writeToDisk(outputPath, func(writer io.Writer) error {
+		_, err := c.DownloadDatabaseTo(name, format, writer, nil)
+		return err
 	})
 }
 
@@ -144,6 +155,19 @@ func (c *UserContext) ExportDatabase(databaseName string, gzip bool) ([]byte, er
 	return export, nil
 }
 
+// ExportDatabaseTo (user) is like ExportDatabase, but streams the export to the writer without holding it in memory,
+// returning the number of bytes written. The progress function is optional.
+func (c *UserContext) ExportDatabaseTo(databaseName string, gzip bool, writer io.Writer, progress ProgressFunc) (int64, error) {
+	databaseName = c.addUsernamePrefix(databaseName)
+
+	written, err := c.makeRequestNewStream("db-manage/databases/"+databaseName+"/export?gzip="+strconv.FormatBool(gzip), writer, progress)
+	if err != nil {
+		return written, fmt.Errorf("failed to export database: %w", err)
+	}
+
+	return written, nil
+}
+
 // GetDatabase (user) returns the given database.
 func (c *UserContext) GetDatabase(databaseName string) (*Database, error) {
 	databaseName = c.addUsernamePrefix(databaseName)
@@ -194,25 +218,18 @@ func (c *UserContext) CreatePHPMyAdminLoginURL() (string, error) {
 
 // ImportDatabase (user) imports the given database export into the given database.
 func (c *UserContext) ImportDatabase(databaseName string, emptyExistingDatabase bool, sql []byte) error {
-	databaseName = c.addUsernamePrefix(databaseName)
-
-	var byteBuffer bytes.Buffer
-	multipartWriter := multipart.NewWriter(&byteBuffer)
-
-	formFile, err := multipartWriter.CreateFormFile("sqlfile", "filename")
-	if err != nil {
-		return fmt.Errorf("failed to create form file: %w", err)
-	}
+	return c.ImportDatabaseFrom(databaseName, emptyExistingDatabase, bytes.NewReader(sql), int64(len(sql)), nil)
+}
 
-	if _, err = formFile.Write(sql); err != nil {
-		return fmt.Errorf("failed to write to form file: %w", err)
-	}
+// ImportDatabaseFrom (user) is like ImportDatabase, but streams the export from the reader rather than holding it in
+// memory. The size is used to report progress and to check that the whole export was sent; pass -1 if it isn't known.
+// The progress function is optional.
+func (c *UserContext) ImportDatabaseFrom(databaseName string, emptyExistingDatabase bool, reader io.Reader, size int64, progress ProgressFunc) error {
+	databaseName = c.addUsernamePrefix(databaseName)
 
-	if err = multipartWriter.Close(); err != nil {
-		return fmt.Errorf("failed to close multipart writer: %w", err)
-	}
+	body, contentType, contentLength := pipeMultipart("sqlfile", "filename", withProgressReader(reader, size, progress), size)
 
-	if _, err = c.uploadFile(http.MethodPost, "/api/db-manage/databases/"+databaseName+"/import?clean="+strconv.FormatBool(emptyExistingDatabase), byteBuffer.Bytes(), nil, multipartWriter.FormDataContentType()); err != nil {
+	if _, err := c.uploadStream(context.Background(), http.MethodPost, "/api/db-manage/databases/"+databaseName+"/import?clean="+strconv.FormatBool(emptyExistingDatabase), body, contentLength, nil, contentType); err != nil {
 		return err
 	}
 
@@ -246,3 +263,22 @@ func (c *UserContext) UpdateDatabaseUserPassword(username string, password strin
 
 	return nil
 }
+
+// databaseDownloadName returns the filename DA serves the given database under, prepending the username if the caller
+// didn't, and checks the format is valid.
+func (c *UserContext) databaseDownloadName(name string, format DatabaseFormat) (string, error) {
+	switch format {
+	case DatabaseFormatGZ, DatabaseFormatSQL:
+		break
+	default:
+		return "", fmt.Errorf("invalid database format: %v", format)
+	}
+
+	name = name + "." + string(format)
+
+	if !strings.Contains(name, c.GetMyUsername()+"_") {
+		name = c.GetMyUsername() + "_" + name
+	}
+
+	return name, nil
+}
@@ -64,9 +64,13 @@ func New(serverURL string, timeout time.Duration, cacheEnabled bool, debug bool)
 
 	if cacheEnabled {
 		api.cache.domains = make(map[string]Domain)
+		api.cache.domainsMutex = &sync.Mutex{}
 		api.cache.emailAccounts = make(map[string]EmailAccount)
+		api.cache.emailAccountsMutex = &sync.Mutex{}
 		api.cache.packages = make(map[string]Package)
+		api.cache.packagesMutex = &sync.Mutex{}
 		api.cache.users = make(map[string]User)
+		api.cache.usersMutex = &sync.Mutex{}
 	}
 
 	api.httpClient = &http.Client{Timeout: timeout}
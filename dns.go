@@ -1,7 +1,6 @@
 package directadmin
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -11,28 +10,38 @@ import (
 )
 
 type DNSRecord struct {
+	// Flags is the CAA record's flags.
+	Flags int    `json:"flags,omitempty"`
 	Name  string `json:"name"`
-	TTL   int    `json:"ttl"`
-	Type  string `json:"type"`
+	// Port is the SRV record's port.
+	Port int `json:"port,omitempty"`
+	// Priority is the MX record's preference, or the SRV record's priority.
+	Priority int `json:"priority,omitempty"`
+	// Tag is the CAA record's property tag, e.g. "issue".
+	Tag  string `json:"tag,omitempty"`
+	TTL  int    `json:"ttl"`
+	Type string `json:"type"`
+	// Value is the record's data. For MX and SRV records it's the target hostname, for CAA records it's the property
+	// value, and for TXT records it's the full unquoted text, which is split into 255 byte strings automatically.
 	Value string `json:"value"`
+	// Weight is the SRV record's weight.
+	Weight int `json:"weight,omitempty"`
+
+	// rawValue is the value exactly as DA stored it, set on records read from DA, so they can be selected for edits
+	// and deletes even if DA's formatting differs from ours.
+	rawValue string
 }
 
 // CheckDNSRecordExists (user) checks if the given dns record exists on the server.
 //
 // checkField can be either "name" or "value".
 func (c *UserContext) CheckDNSRecordExists(checkField string, domain string, dnsRecord DNSRecord) error {
-	body := url.Values{
-		"check":  {checkField},
-		"domain": {domain},
-		"name":   {dnsRecord.Name},
-		"record": {dnsRecord.Type},
-		"type":   {"dns"},
-		"value":  {dnsRecord.Value},
-	}
-
-	if dnsRecord.Type == "MX" {
-		body.Set("mx_value", dnsRecord.Value)
-	}
+	body := dnsRecord.formValues()
+	body.Set("check", checkField)
+	body.Set("domain", domain)
+	body.Set("record", body.Get("type"))
+	body.Set("type", "dns")
+	body.Del("ttl")
 
 	return c.checkObjectExists(body)
 }
@@ -41,15 +50,8 @@ func (c *UserContext) CheckDNSRecordExists(checkField string, domain string, dns
 func (c *UserContext) CreateDNSRecord(domain string, dnsRecord DNSRecord) error {
 	var response apiGenericResponse
 
-	rawDNSRecordData := dnsRecord.translate()
-
-	body := url.Values{
-		"domain": {domain},
-		"name":   {rawDNSRecordData.Name},
-		"ttl":    {cast.ToString(rawDNSRecordData.TTL)},
-		"type":   {rawDNSRecordData.Type},
-		"value":  {rawDNSRecordData.Value},
-	}
+	body := dnsRecord.formValues()
+	body.Set("domain", domain)
 
 	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_CONTROL?action=add&action_pointers=yes", body, &response); err != nil {
 		return err
@@ -64,22 +66,21 @@ func (c *UserContext) CreateDNSRecord(domain string, dnsRecord DNSRecord) error
 
 // DeleteDNSRecords (user) deletes all the specified DNS records for the session user.
 func (c *UserContext) DeleteDNSRecords(dnsRecords ...DNSRecord) error {
-	var response apiGenericResponse
+	return c.deleteDNSRecords(url.Values{}, dnsRecords)
+}
 
+// DeleteDomainDNSRecords (user) deletes all the specified DNS records from the given domain in a single call.
+func (c *UserContext) DeleteDomainDNSRecords(domain string, dnsRecords ...DNSRecord) error {
 	body := url.Values{}
-	dnsRecordMap := make(map[string][]string)
+	body.Set("domain", domain)
 
-	for _, dnsRecord := range dnsRecords {
-		dnsType := strings.ToLower(dnsRecord.Type)
+	return c.deleteDNSRecords(body, dnsRecords)
+}
 
-		dnsRecordMap[dnsType] = append(dnsRecordMap[dnsType], fmt.Sprintf("name=%v&value=%v", dnsRecord.Name, dnsRecord.Value))
-	}
+func (c *UserContext) deleteDNSRecords(body url.Values, dnsRecords []DNSRecord) error {
+	var response apiGenericResponse
 
-	for dnsRecordType, dnsRecordData := range dnsRecordMap {
-		for index, data := range dnsRecordData {
-			body.Set(dnsRecordType+"recs"+cast.ToString(index), data)
-		}
-	}
+	setDNSRecordSelectors(body, dnsRecords)
 
 	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_CONTROL?action=select&delete=yes", body, &response); err != nil {
 		return err
@@ -94,40 +95,33 @@ func (c *UserContext) DeleteDNSRecords(dnsRecords ...DNSRecord) error {
 
 // GetDNSRecords (user) returns the given domain's DNS records.
 func (c *UserContext) GetDNSRecords(domain string) ([]DNSRecord, error) {
-	var dnsRecords []DNSRecord
-	rawDNSRecords := struct {
-		DNSRecords []rawDNSRecord `json:"records"`
-	}{}
-
-	if _, err := c.makeRequestOld(http.MethodGet, "API_DNS_CONTROL?domain="+domain, nil, &rawDNSRecords); err != nil {
+	rawZone, err := c.getRawDNSZone(domain)
+	if err != nil {
 		return nil, err
 	}
 
-	for _, dnsRecord := range rawDNSRecords.DNSRecords {
-		dnsRecords = append(dnsRecords, dnsRecord.translate())
-	}
+	return rawZone.translateRecords()
+}
+
+// getRawDNSZone (user) returns DA's full DNS control response for the given domain, including the SOA data that
+// GetDNSRecords doesn't expose.
+func (c *UserContext) getRawDNSZone(domain string) (*rawDNSZone, error) {
+	var rawZone rawDNSZone
 
-	if len(dnsRecords) == 0 {
-		return nil, errors.New("no dns records were found")
+	if _, err := c.makeRequestOld(http.MethodGet, "API_DNS_CONTROL?domain="+domain, nil, &rawZone); err != nil {
+		return nil, err
 	}
 
-	return dnsRecords, nil
+	return &rawZone, nil
 }
 
 // UpdateDNSRecord (user) updates the given DNS record for the given domain.
 func (c *UserContext) UpdateDNSRecord(domain string, originalDNSRecord DNSRecord, updatedDNSRecord DNSRecord) error {
 	var response apiGenericResponse
 
-	rawDNSRecordData := updatedDNSRecord.translate()
-
-	body := url.Values{
-		"domain": {domain},
-		"name":   {rawDNSRecordData.Name},
-		"ttl":    {cast.ToString(rawDNSRecordData.TTL)},
-		"type":   {rawDNSRecordData.Type},
-		"value":  {rawDNSRecordData.Value},
-		strings.ToLower(originalDNSRecord.Type) + "recs0": {fmt.Sprintf("name=%v&value=%v", originalDNSRecord.Name, originalDNSRecord.Value)},
-	}
+	body := updatedDNSRecord.formValues()
+	body.Set("domain", domain)
+	body.Set(strings.ToLower(originalDNSRecord.Type)+"recs0", originalDNSRecord.selector())
 
 	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_CONTROL?action=edit&action_pointers=yes", body, &response); err != nil {
 		return err
@@ -139,3 +133,43 @@ func (c *UserContext) UpdateDNSRecord(domain string, originalDNSRecord DNSRecord
 
 	return nil
 }
+
+// relativeDNSName returns the given record name relative to the domain's zone, with "@" for the apex. Names outside the
+// zone are returned fully qualified.
+func relativeDNSName(name string, domain string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if name == "" || name == "@" {
+		return "@"
+	}
+
+	absolute := strings.HasSuffix(name, ".")
+	name = strings.TrimSuffix(name, ".")
+
+	if name == domain {
+		return "@"
+	}
+
+	if relative, ok := strings.CutSuffix(name, "."+domain); ok {
+		return relative
+	}
+
+	if absolute {
+		return name + "."
+	}
+
+	return name
+}
+
+// setDNSRecordSelectors adds the "<type>recs<n>" fields DA uses to select existing records to the given form body.
+func setDNSRecordSelectors(body url.Values, dnsRecords []DNSRecord) {
+	counters := make(map[string]int)
+
+	for _, dnsRecord := range dnsRecords {
+		dnsType := strings.ToLower(dnsRecord.Type)
+
+		body.Set(dnsType+"recs"+cast.ToString(counters[dnsType]), dnsRecord.selector())
+		counters[dnsType]++
+	}
+}
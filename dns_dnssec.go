@@ -0,0 +1,260 @@
+package directadmin
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// dnssecAlgorithms maps DNSSEC algorithm numbers to their mnemonics (RFC 8624).
+var dnssecAlgorithms = map[int]string{
+	5:  "RSASHA1",
+	7:  "RSASHA1-NSEC3-SHA1",
+	8:  "RSASHA256",
+	10: "RSASHA512",
+	13: "ECDSAP256SHA256",
+	14: "ECDSAP384SHA384",
+	15: "ED25519",
+	16: "ED448",
+}
+
+type (
+	DNSSECKey struct {
+		Algorithm     int    `json:"algorithm"`
+		AlgorithmName string `json:"algorithmName"`
+		// Flags is 257 for key signing keys and 256 for zone signing keys.
+		Flags     int    `json:"flags"`
+		KeyTag    int    `json:"keyTag"`
+		Protocol  int    `json:"protocol"`
+		PublicKey string `json:"publicKey"`
+	}
+
+	DNSSECStatus struct {
+		DNSKEYs   []DNSSECKey `json:"dnskeys"`
+		Domain    string      `json:"domain"`
+		DSRecords []DSRecord  `json:"dsRecords"`
+		// KeysGenerated is true once DA has generated keys for the zone, even if it isn't signed yet.
+		KeysGenerated bool `json:"keysGenerated"`
+		Signed        bool `json:"signed"`
+	}
+
+	DSRecord struct {
+		Algorithm     int    `json:"algorithm"`
+		AlgorithmName string `json:"algorithmName"`
+		Digest        string `json:"digest"`
+		DigestType    int    `json:"digestType"`
+		KeyTag        int    `json:"keyTag"`
+	}
+)
+
+// IsKSK returns whether the key is a key signing key, i.e. the key whose DS record is published at the registrar.
+func (k *DNSSECKey) IsKSK() bool {
+	return k.Flags&1 == 1
+}
+
+// String returns the DS record's data in zone file presentation format.
+func (d *DSRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest)
+}
+
+// DisableDNSSEC (user) removes DNSSEC signing from the given domain's zone. Remove the DS records at the registrar
+// before calling this, otherwise resolvers will fail to validate the zone.
+func (c *UserContext) DisableDNSSEC(domain string) error {
+	return c.dnssecAction(domain, "unsign", "DNSSEC Removed")
+}
+
+// EnableDNSSEC (user) generates keys for the given domain if needed, then signs its zone. Call GetDNSSECStatus
+// afterward to get the DS records for the registrar.
+func (c *UserContext) EnableDNSSEC(domain string) error {
+	session, err := c.GetSessionInfo()
+	if err != nil {
+		return fmt.Errorf("failed to get session info: %w", err)
+	}
+
+	if session.ConfigFeatures.DNSSEC == 0 {
+		return errors.New("DNSSEC is not enabled on this server")
+	}
+
+	status, err := c.GetDNSSECStatus(domain)
+	if err != nil {
+		return err
+	}
+
+	if !status.KeysGenerated {
+		if err = c.dnssecAction(domain, "generate_keys", "Keys Generated"); err != nil {
+			return err
+		}
+	}
+
+	return c.dnssecAction(domain, "sign", "Zone Signed")
+}
+
+// GetDNSSECStatus (user) returns whether the given domain's zone is signed, along with its DNSKEY and DS records.
+func (c *UserContext) GetDNSSECStatus(domain string) (*DNSSECStatus, error) {
+	var rawStatus struct {
+		DNSKEY        string `json:"dnskey"`
+		DS            string `json:"ds"`
+		KeysGenerated string `json:"keys_exist"`
+		Signed        string `json:"signed"`
+	}
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_DNS_CONTROL?action=dnssec&domain="+domain, nil, &rawStatus); err != nil {
+		return nil, fmt.Errorf("failed to get DNSSEC status: %w", err)
+	}
+
+	status := &DNSSECStatus{
+		DNSKEYs:       []DNSSECKey{},
+		Domain:        domain,
+		DSRecords:     []DSRecord{},
+		KeysGenerated: parseOnOff(rawStatus.KeysGenerated),
+		Signed:        parseOnOff(rawStatus.Signed),
+	}
+
+	for _, line := range strings.Split(rawStatus.DNSKEY, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		key, err := parseDNSKEY(line)
+		if err != nil {
+			return nil, err
+		}
+
+		status.DNSKEYs = append(status.DNSKEYs, *key)
+	}
+
+	for _, line := range strings.Split(rawStatus.DS, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		ds, err := parseDSRecord(line)
+		if err != nil {
+			return nil, err
+		}
+
+		status.DSRecords = append(status.DSRecords, *ds)
+	}
+
+	return status, nil
+}
+
+func (c *UserContext) dnssecAction(domain string, action string, expectedSuccess string) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("action", "dnssec")
+	body.Set("domain", domain)
+	body.Set("value", action)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_CONTROL", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != expectedSuccess {
+		return fmt.Errorf("failed to %v DNSSEC: %v", strings.ReplaceAll(action, "_", " "), response.Result)
+	}
+
+	return nil
+}
+
+// dnssecRData returns the fields after the given record type, so both bare record data and full zone file lines
+// (owner, TTL and class included) are accepted.
+func dnssecRData(line string, recordType string) []string {
+	fields := strings.Fields(line)
+
+	for i, field := range fields {
+		if strings.EqualFold(field, recordType) {
+			return fields[i+1:]
+		}
+	}
+
+	return fields
+}
+
+// dnskeyTag calculates a DNSKEY's key tag as described in RFC 4034, appendix B.
+func dnskeyTag(flags int, protocol int, algorithm int, publicKey []byte) int {
+	rdata := append([]byte{byte(flags >> 8), byte(flags), byte(protocol), byte(algorithm)}, publicKey...)
+
+	var accumulator uint32
+
+	for i, b := range rdata {
+		if i&1 == 0 {
+			accumulator += uint32(b) << 8
+		} else {
+			accumulator += uint32(b)
+		}
+	}
+
+	accumulator += accumulator >> 16 & 0xFFFF
+
+	return int(accumulator & 0xFFFF)
+}
+
+// parseDNSKEY parses a DNSKEY record, e.g. "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0d...".
+func parseDNSKEY(line string) (*DNSSECKey, error) {
+	fields := dnssecRData(line, "DNSKEY")
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid DNSKEY record: %v", line)
+	}
+
+	var key DNSSECKey
+	var err error
+
+	if key.Flags, err = strconv.Atoi(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY flags: %v", fields[0])
+	}
+
+	if key.Protocol, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY protocol: %v", fields[1])
+	}
+
+	if key.Algorithm, err = strconv.Atoi(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY algorithm: %v", fields[2])
+	}
+
+	// The key may be split over several whitespace-separated fields.
+	key.AlgorithmName = dnssecAlgorithms[key.Algorithm]
+	key.PublicKey = strings.Join(fields[3:], "")
+
+	publicKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY public key: %w", err)
+	}
+
+	key.KeyTag = dnskeyTag(key.Flags, key.Protocol, key.Algorithm, publicKey)
+
+	return &key, nil
+}
+
+// parseDSRecord parses a DS record, e.g. "2371 13 2 1F987CC6583E92DF0890718C42...".
+func parseDSRecord(line string) (*DSRecord, error) {
+	fields := dnssecRData(line, "DS")
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid DS record: %v", line)
+	}
+
+	var ds DSRecord
+	var err error
+
+	if ds.KeyTag, err = strconv.Atoi(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid DS key tag: %v", fields[0])
+	}
+
+	if ds.Algorithm, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid DS algorithm: %v", fields[1])
+	}
+
+	if ds.DigestType, err = strconv.Atoi(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid DS digest type: %v", fields[2])
+	}
+
+	ds.AlgorithmName = dnssecAlgorithms[ds.Algorithm]
+	ds.Digest = strings.ToUpper(strings.Join(fields[3:], ""))
+
+	return &ds, nil
+}
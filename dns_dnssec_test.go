@@ -0,0 +1,24 @@
+package directadmin
+
+import "testing"
+
+func TestParseDNSSECRecords(t *testing.T) {
+	// Example key and DS record from RFC 4034, section 5.4.
+	key, err := parseDNSKEY(`dskey.example.com. 86400 IN DNSKEY 256 3 5 AQOeiiR0GOMYkDshWoSKz9Xz fwJr1AYtsmx3TGkJaNXVbfi/ 2pHm822aJ5iI9BMzNXxeYCmZ DRD99WYwYqUSdjMmmAphXdvx egXd/M5+X7OrzKBaMbCVdFLU Uh6DhweJBjEVv5f2wwjM9Xzc nOf+EPbtG9DMBmADjFDc2w/r ljwvFw==`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.KeyTag != 60485 || key.AlgorithmName != "RSASHA1" || key.IsKSK() {
+		t.Fatalf("unexpected DNSKEY: %+v", key)
+	}
+
+	ds, err := parseDSRecord("dskey.example.com. 86400 IN DS 60485 5 1 2BB183AF5F22588179A53B0A 98631FAD1A292118")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ds.KeyTag != key.KeyTag || ds.DigestType != 1 || ds.String() != "60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118" {
+		t.Fatalf("unexpected DS record: %+v", ds)
+	}
+}
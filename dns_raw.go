@@ -1,28 +1,224 @@
 package directadmin
 
-import "github.com/spf13/cast"
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
 
-type rawDNSRecord struct {
-	Name  string `json:"name"`
-	TTL   string `json:"ttl"`
-	Type  string `json:"type"`
-	Value string `json:"value"`
+	"github.com/spf13/cast"
+)
+
+// txtChunkSize is the maximum length of a single TXT character-string.
+const txtChunkSize = 255
+
+type (
+	rawDNSRecord struct {
+		Name  string `json:"name"`
+		TTL   string `json:"ttl"`
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+
+	rawDNSZone struct {
+		DefaultTTL string         `json:"ttl_value"`
+		DNSRecords []rawDNSRecord `json:"records"`
+		SOA        rawZoneSOA     `json:"soa"`
+	}
+
+	rawZoneSOA struct {
+		Expire     string `json:"expire"`
+		Hostmaster string `json:"email"`
+		Minimum    string `json:"minimum"`
+		PrimaryNS  string `json:"ns"`
+		Refresh    string `json:"refresh"`
+		Retry      string `json:"retry"`
+		Serial     string `json:"serial"`
+	}
+)
+
+// translateRecords returns the zone's DNS records, or an error if it has none.
+func (r *rawDNSZone) translateRecords() ([]DNSRecord, error) {
+	dnsRecords := make([]DNSRecord, 0, len(r.DNSRecords))
+
+	for _, dnsRecord := range r.DNSRecords {
+		translated := dnsRecord.translate()
+		translated.rawValue = dnsRecord.Value
+
+		dnsRecords = append(dnsRecords, translated)
+	}
+
+	if len(dnsRecords) == 0 {
+		return nil, errors.New("no dns records were found")
+	}
+
+	return dnsRecords, nil
 }
 
+// translateSOA returns a ZoneSOA object.
+func (r *rawDNSZone) translateSOA() ZoneSOA {
+	return ZoneSOA{
+		DefaultTTL: cast.ToInt(r.DefaultTTL),
+		Expire:     cast.ToInt(r.SOA.Expire),
+		Hostmaster: r.SOA.Hostmaster,
+		Minimum:    cast.ToInt(r.SOA.Minimum),
+		PrimaryNS:  r.SOA.PrimaryNS,
+		Refresh:    cast.ToInt(r.SOA.Refresh),
+		Retry:      cast.ToInt(r.SOA.Retry),
+		Serial:     cast.ToUint32(r.SOA.Serial),
+	}
+}
+
+// formValues returns the fields DA's DNS control form expects when adding or editing the record.
+func (d *DNSRecord) formValues() url.Values {
+	rawDNSRecordData := d.translate()
+
+	body := url.Values{
+		"name":  {rawDNSRecordData.Name},
+		"ttl":   {rawDNSRecordData.TTL},
+		"type":  {rawDNSRecordData.Type},
+		"value": {rawDNSRecordData.Value},
+	}
+
+	// DA splits MX and CAA records over extra form fields rather than parsing the combined value.
+	switch rawDNSRecordData.Type {
+	case "CAA":
+		body.Set("caa_flag", strconv.Itoa(d.Flags))
+		body.Set("caa_tag", d.Tag)
+		body.Set("caa_value", d.Value)
+	case "MX":
+		body.Set("mx_value", d.Value)
+		body.Set("value", strconv.Itoa(d.Priority))
+	}
+
+	return body
+}
+
+// selector returns the record in the "<type>recs<n>" form DA uses to select existing records for editing or deletion.
+// Records read from DA are selected by their stored value, since DA only matches it verbatim.
+func (d *DNSRecord) selector() string {
+	rawDNSRecordData := d.translate()
+	if d.rawValue != "" {
+		rawDNSRecordData.Value = d.rawValue
+	}
+
+	return url.Values{
+		"name":  {rawDNSRecordData.Name},
+		"value": {rawDNSRecordData.Value},
+	}.Encode()
+}
+
+// translate returns a rawDNSRecord object with DA's combined value format.
 func (d *DNSRecord) translate() rawDNSRecord {
-	return rawDNSRecord{
+	rawDNSRecordData := rawDNSRecord{
 		Name:  d.Name,
-		TTL:   cast.ToString(d.TTL),
-		Type:  d.Type,
+		Type:  strings.ToUpper(d.Type),
 		Value: d.Value,
 	}
+
+	if d.TTL > 0 {
+		rawDNSRecordData.TTL = strconv.Itoa(d.TTL)
+	}
+
+	switch rawDNSRecordData.Type {
+	case "CAA":
+		rawDNSRecordData.Value = strconv.Itoa(d.Flags) + " " + d.Tag + " " + quoteCharacterString(d.Value)
+	case "MX":
+		rawDNSRecordData.Value = strconv.Itoa(d.Priority) + " " + d.Value
+	case "SRV":
+		rawDNSRecordData.Value = strconv.Itoa(d.Priority) + " " + strconv.Itoa(d.Weight) + " " + strconv.Itoa(d.Port) + " " + d.Value
+	case "TXT":
+		rawDNSRecordData.Value = splitTXT(d.Value)
+	}
+
+	return rawDNSRecordData
 }
 
+// translate returns a DNSRecord object, with the combined value split into its typed fields.
 func (d *rawDNSRecord) translate() DNSRecord {
-	return DNSRecord{
+	dnsRecord := DNSRecord{
 		Name:  d.Name,
 		TTL:   cast.ToInt(d.TTL),
-		Type:  d.Type,
+		Type:  strings.ToUpper(d.Type),
 		Value: d.Value,
 	}
+
+	fields := strings.Fields(d.Value)
+
+	switch dnsRecord.Type {
+	case "CAA":
+		if len(fields) >= 3 {
+			dnsRecord.Flags = cast.ToInt(fields[0])
+			dnsRecord.Tag = fields[1]
+			// The value may contain spaces, so take everything after the tag.
+			dnsRecord.Value = unquoteTXT(strings.TrimSpace(d.Value[strings.Index(d.Value, fields[1])+len(fields[1]):]))
+		}
+	case "MX":
+		if len(fields) == 2 {
+			dnsRecord.Priority = cast.ToInt(fields[0])
+			dnsRecord.Value = fields[1]
+		}
+	case "SRV":
+		if len(fields) == 4 {
+			dnsRecord.Priority = cast.ToInt(fields[0])
+			dnsRecord.Weight = cast.ToInt(fields[1])
+			dnsRecord.Port = cast.ToInt(fields[2])
+			dnsRecord.Value = fields[3]
+		}
+	case "TXT":
+		dnsRecord.Value = unquoteTXT(d.Value)
+	}
+
+	return dnsRecord
+}
+
+// quoteCharacterString quotes and escapes a single zone file character-string.
+func quoteCharacterString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// splitTXT quotes the given TXT value, splitting it into 255 byte character-strings where necessary.
+func splitTXT(value string) string {
+	var chunks []string
+
+	for {
+		chunk := value
+		if len(chunk) > txtChunkSize {
+			chunk = chunk[:txtChunkSize]
+		}
+
+		chunks = append(chunks, quoteCharacterString(chunk))
+
+		value = value[len(chunk):]
+		if value == "" {
+			break
+		}
+	}
+
+	return strings.Join(chunks, " ")
+}
+
+// unquoteTXT joins the quoted character-strings of a TXT record value. Unquoted values are returned as-is.
+func unquoteTXT(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, `"`) {
+		return value
+	}
+
+	var builder strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(value); i++ {
+		switch char := value[i]; {
+		case char == '\\' && inQuotes && i+1 < len(value):
+			i++
+			builder.WriteByte(value[i])
+		case char == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+			builder.WriteByte(char)
+		}
+	}
+
+	return builder.String()
 }
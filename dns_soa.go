@@ -0,0 +1,156 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ZoneSOA struct {
+	// DefaultTTL is the zone's $TTL, used by records without their own TTL. It's read-only here, use SetDefaultTTL
+	// to change it.
+	DefaultTTL int `json:"defaultTTL" yaml:"defaultTTL"`
+	Expire     int `json:"expire" yaml:"expire"`
+	// Hostmaster is read-only, UpdateZoneSOA can't change it.
+	Hostmaster string `json:"hostmaster" yaml:"hostmaster"`
+	Minimum    int    `json:"minimum" yaml:"minimum"`
+	// PrimaryNS is read-only, UpdateZoneSOA can't change it.
+	PrimaryNS string `json:"primaryNS" yaml:"primaryNS"`
+	Refresh   int    `json:"refresh" yaml:"refresh"`
+	Retry     int    `json:"retry" yaml:"retry"`
+	Serial    uint32 `json:"serial" yaml:"serial"`
+}
+
+// Validate checks the SOA timers locally.
+func (s *ZoneSOA) Validate() error {
+	if s.Refresh <= 0 || s.Retry <= 0 || s.Expire <= 0 || s.Minimum <= 0 {
+		return errors.New("refresh, retry, expire and minimum must all be positive")
+	}
+
+	if s.Retry >= s.Refresh {
+		return fmt.Errorf("retry (%d) must be less than refresh (%d)", s.Retry, s.Refresh)
+	}
+
+	if s.Expire <= s.Refresh+s.Retry {
+		return fmt.Errorf("expire (%d) must be greater than refresh + retry (%d)", s.Expire, s.Refresh+s.Retry)
+	}
+
+	return nil
+}
+
+// NextSOASerial returns the next serial in the YYYYMMDDnn convention for the given time. If the current serial is
+// already at or past today's date, it's incremented instead, so the result is always newer than the current serial.
+func NextSOASerial(current uint32, now time.Time) uint32 {
+	dateSerial, _ := strconv.ParseUint(now.UTC().Format("20060102")+"00", 10, 32)
+
+	if serialNewer(uint32(dateSerial), current) {
+		return uint32(dateSerial)
+	}
+
+	return current + 1
+}
+
+// GetZoneSOA (user) returns the SOA fields and default TTL for the given domain's zone.
+func (c *UserContext) GetZoneSOA(domain string) (*ZoneSOA, error) {
+	rawZone, err := c.getRawDNSZone(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	soa := rawZone.translateSOA()
+
+	return &soa, nil
+}
+
+// SetDefaultTTL (user) sets the zone's default TTL, used by records without their own TTL.
+func (c *UserContext) SetDefaultTTL(domain string, ttl int) error {
+	var response apiGenericResponse
+
+	if ttl <= 0 {
+		return fmt.Errorf("invalid TTL: %d", ttl)
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("ttl", strconv.Itoa(ttl))
+	body.Set("ttl_select", "custom")
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_CONTROL?action=ttl", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "TTL Saved" {
+		return fmt.Errorf("failed to set default TTL: %v", response.Result)
+	}
+
+	return nil
+}
+
+// UpdateZoneSOA (user) updates the zone's SOA timers and serial. A zero serial leaves the serial for DA to manage,
+// otherwise it must not be older than the current serial (using RFC 1982 serial arithmetic). DefaultTTL is ignored.
+// Hostmaster and PrimaryNS can't be changed through DA's SOA form, so an error is returned if either is set to
+// something other than its current value. Leave them empty, or pass them through from GetZoneSOA.
+func (c *UserContext) UpdateZoneSOA(domain string, soa ZoneSOA) error {
+	var response apiGenericResponse
+
+	if err := soa.Validate(); err != nil {
+		return fmt.Errorf("invalid SOA: %w", err)
+	}
+
+	if soa.Hostmaster != "" || soa.PrimaryNS != "" || soa.Serial != 0 {
+		current, err := c.GetZoneSOA(domain)
+		if err != nil {
+			return fmt.Errorf("failed to get current SOA: %w", err)
+		}
+
+		if err = checkSOAUpdate(*current, soa); err != nil {
+			return err
+		}
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("expire", strconv.Itoa(soa.Expire))
+	body.Set("minimum", strconv.Itoa(soa.Minimum))
+	body.Set("refresh", strconv.Itoa(soa.Refresh))
+	body.Set("retry", strconv.Itoa(soa.Retry))
+
+	if soa.Serial != 0 {
+		body.Set("serial", strconv.FormatUint(uint64(soa.Serial), 10))
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DNS_CONTROL?action=soa", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "SOA Saved" {
+		return fmt.Errorf("failed to update SOA: %v", response.Result)
+	}
+
+	return nil
+}
+
+// checkSOAUpdate checks that the updated SOA doesn't change the read-only fields or move the serial backwards.
+func checkSOAUpdate(current ZoneSOA, updated ZoneSOA) error {
+	if updated.Hostmaster != "" && updated.Hostmaster != current.Hostmaster {
+		return fmt.Errorf("hostmaster can't be changed from %v", current.Hostmaster)
+	}
+
+	if updated.PrimaryNS != "" && updated.PrimaryNS != current.PrimaryNS {
+		return fmt.Errorf("primary name server can't be changed from %v", current.PrimaryNS)
+	}
+
+	if updated.Serial != 0 && updated.Serial != current.Serial && !serialNewer(updated.Serial, current.Serial) {
+		return fmt.Errorf("serial %d would go backwards from the current serial %d", updated.Serial, current.Serial)
+	}
+
+	return nil
+}
+
+// serialNewer returns whether serial a is newer than serial b, using RFC 1982 serial number arithmetic.
+func serialNewer(a uint32, b uint32) bool {
+	return a != b && a-b < 1<<31
+}
@@ -0,0 +1,54 @@
+package directadmin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSOASerial(t *testing.T) {
+	if !serialNewer(2025101801, 2025101800) || serialNewer(2025101800, 2025101801) || serialNewer(5, 5) {
+		t.Fatal("unexpected serial comparison")
+	}
+
+	// Serials wrap around, so a small serial can be newer than a large one.
+	if !serialNewer(10, 4294967290) {
+		t.Fatal("expected wrapped serial to be newer")
+	}
+
+	now := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := map[uint32]uint32{
+		2025101705: 2025101800,
+		2025101800: 2025101801,
+		2025101899: 2025101900,
+		7:          2025101800,
+	}
+
+	for current, expected := range tests {
+		if next := NextSOASerial(current, now); next != expected {
+			t.Errorf("%d: expected %d, got %d", current, expected, next)
+		}
+	}
+}
+
+func TestCheckSOAUpdate(t *testing.T) {
+	current := ZoneSOA{Hostmaster: "hostmaster.example.com.", PrimaryNS: "ns1.example.com.", Serial: 2025101800}
+
+	tests := []struct {
+		updated ZoneSOA
+		valid   bool
+	}{
+		{updated: ZoneSOA{}, valid: true},
+		{updated: current, valid: true},
+		{updated: ZoneSOA{Serial: 2025101801}, valid: true},
+		{updated: ZoneSOA{Serial: 2025101700}, valid: false},
+		{updated: ZoneSOA{Hostmaster: "admin.example.com."}, valid: false},
+		{updated: ZoneSOA{PrimaryNS: "ns2.example.com."}, valid: false},
+	}
+
+	for _, test := range tests {
+		if err := checkSOAUpdate(current, test.updated); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid %v, got %v", test.updated, test.valid, err)
+		}
+	}
+}
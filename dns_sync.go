@@ -0,0 +1,233 @@
+package directadmin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// singleValueDNSTypes are the record types a name can only have one of.
+var singleValueDNSTypes = map[string]bool{"CNAME": true, "SOA": true}
+
+type (
+	// DNSPlan lists the changes needed to bring a domain's zone to a desired state. It can be printed, or applied with
+	// ApplyDNSPlan.
+	DNSPlan struct {
+		Adds    []DNSRecord     `json:"adds"`
+		Deletes []DNSRecord     `json:"deletes"`
+		Domain  string          `json:"domain"`
+		Edits   []DNSRecordEdit `json:"edits"`
+	}
+
+	DNSRecordEdit struct {
+		From DNSRecord `json:"from"`
+		To   DNSRecord `json:"to"`
+	}
+
+	DNSSyncOptions struct {
+		// DryRun only computes the plan, without applying it.
+		DryRun bool
+		// KeepExtra leaves records that aren't in the desired list alone, rather than deleting them. CNAME and SOA
+		// records can only have one value, so they're still edited when the desired list has a different value.
+		KeepExtra bool
+		// ProtectNS prevents NS records from being deleted or edited.
+		ProtectNS bool
+		// ProtectSOA prevents SOA records from being deleted or edited.
+		ProtectSOA bool
+	}
+)
+
+// Empty returns whether the plan has no changes.
+func (p *DNSPlan) Empty() bool {
+	return len(p.Adds) == 0 && len(p.Deletes) == 0 && len(p.Edits) == 0
+}
+
+// String returns a human-readable summary of the plan, one change per line.
+func (p *DNSPlan) String() string {
+	if p.Empty() {
+		return "no changes for " + p.Domain + "\n"
+	}
+
+	formatRecord := func(dnsRecord DNSRecord) string {
+		rawDNSRecordData := dnsRecord.translate()
+
+		ttl := rawDNSRecordData.TTL
+		if ttl == "" {
+			ttl = "default"
+		}
+
+		return fmt.Sprintf("%s %s %s %s", relativeDNSName(dnsRecord.Name, p.Domain), ttl, rawDNSRecordData.Type, rawDNSRecordData.Value)
+	}
+
+	var builder strings.Builder
+
+	for _, dnsRecord := range p.Deletes {
+		builder.WriteString("- " + formatRecord(dnsRecord) + "\n")
+	}
+
+	for _, edit := range p.Edits {
+		builder.WriteString("~ " + formatRecord(edit.From) + " => " + formatRecord(edit.To) + "\n")
+	}
+
+	for _, dnsRecord := range p.Adds {
+		builder.WriteString("+ " + formatRecord(dnsRecord) + "\n")
+	}
+
+	return builder.String()
+}
+
+// ApplyDNSPlan (user) applies the given plan. Deletions are sent in a single call, followed by edits and then adds.
+func (c *UserContext) ApplyDNSPlan(plan *DNSPlan) error {
+	if len(plan.Deletes) > 0 {
+		if err := c.DeleteDomainDNSRecords(plan.Domain, plan.Deletes...); err != nil {
+			return err
+		}
+	}
+
+	for _, edit := range plan.Edits {
+		if err := c.UpdateDNSRecord(plan.Domain, edit.From, edit.To); err != nil {
+			return err
+		}
+	}
+
+	for _, dnsRecord := range plan.Adds {
+		if err := c.CreateDNSRecord(plan.Domain, dnsRecord); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncDNSRecords (user) fetches the domain's current zone, works out the smallest set of changes needed to match the
+// desired records, and applies them unless opts.DryRun is set. The plan is returned either way.
+func (c *UserContext) SyncDNSRecords(domain string, desired []DNSRecord, opts DNSSyncOptions) (*DNSPlan, error) {
+	current, err := c.GetDNSRecords(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dns records: %w", err)
+	}
+
+	plan := planDNSRecords(domain, current, desired, opts)
+
+	if !opts.DryRun {
+		if err = c.ApplyDNSPlan(plan); err != nil {
+			return plan, err
+		}
+	}
+
+	return plan, nil
+}
+
+// planDNSRecords diffs the current and desired records. Identical records are left alone, records that only differ by
+// TTL are edited, and remaining records with the same name and type are paired up as edits before falling back to
+// adds and deletes.
+func planDNSRecords(domain string, current []DNSRecord, desired []DNSRecord, opts DNSSyncOptions) *DNSPlan {
+	plan := &DNSPlan{
+		Adds:    []DNSRecord{},
+		Deletes: []DNSRecord{},
+		Domain:  domain,
+		Edits:   []DNSRecordEdit{},
+	}
+
+	protected := func(dnsRecord DNSRecord) bool {
+		return (opts.ProtectNS && dnsRecord.Type == "NS") || (opts.ProtectSOA && dnsRecord.Type == "SOA")
+	}
+
+	currentByKey := make(map[string]DNSRecord, len(current))
+	for _, dnsRecord := range current {
+		currentByKey[dnsRecordKey(domain, dnsRecord)] = dnsRecord
+	}
+
+	matchedKeys := make(map[string]bool, len(desired))
+
+	// Records left over once exact and TTL-only matches are removed, grouped by name and type.
+	unmatchedDesired := make(map[string][]DNSRecord)
+	var groups []string
+
+	for _, dnsRecord := range desired {
+		key := dnsRecordKey(domain, dnsRecord)
+		if matchedKeys[key] {
+			continue
+		}
+
+		if existing, ok := currentByKey[key]; ok {
+			matchedKeys[key] = true
+
+			if existing.TTL != dnsRecord.TTL && !protected(existing) {
+				plan.Edits = append(plan.Edits, DNSRecordEdit{From: existing, To: dnsRecord})
+			}
+
+			continue
+		}
+
+		group := dnsRecordGroup(domain, dnsRecord)
+		if _, ok := unmatchedDesired[group]; !ok {
+			groups = append(groups, group)
+		}
+
+		unmatchedDesired[group] = append(unmatchedDesired[group], dnsRecord)
+	}
+
+	unmatchedCurrent := make(map[string][]DNSRecord)
+
+	for _, dnsRecord := range current {
+		if matchedKeys[dnsRecordKey(domain, dnsRecord)] || protected(dnsRecord) {
+			continue
+		}
+
+		group := dnsRecordGroup(domain, dnsRecord)
+		_, replaced := unmatchedDesired[group]
+
+		// Extra records are kept, unless a desired record replaces a record that can't have a second value.
+		if opts.KeepExtra && !(replaced && singleValueDNSTypes[strings.ToUpper(dnsRecord.Type)]) {
+			continue
+		}
+
+		unmatchedCurrent[group] = append(unmatchedCurrent[group], dnsRecord)
+
+		if !replaced {
+			plan.Deletes = append(plan.Deletes, dnsRecord)
+		}
+	}
+
+	for _, group := range groups {
+		toAdd := unmatchedDesired[group]
+		toRemove := unmatchedCurrent[group]
+
+		for len(toAdd) > 0 && len(toRemove) > 0 {
+			plan.Edits = append(plan.Edits, DNSRecordEdit{From: toRemove[0], To: toAdd[0]})
+			toAdd, toRemove = toAdd[1:], toRemove[1:]
+		}
+
+		plan.Adds = append(plan.Adds, toAdd...)
+		plan.Deletes = append(plan.Deletes, toRemove...)
+	}
+
+	sort.SliceStable(plan.Deletes, func(i, j int) bool {
+		return dnsRecordGroup(domain, plan.Deletes[i]) < dnsRecordGroup(domain, plan.Deletes[j])
+	})
+
+	return plan
+}
+
+// dnsRecordGroup identifies the record set a record belongs to, by its name and type.
+func dnsRecordGroup(domain string, dnsRecord DNSRecord) string {
+	return relativeDNSName(dnsRecord.Name, domain) + " " + strings.ToUpper(dnsRecord.Type)
+}
+
+// dnsRecordKey identifies a record by its name, type and value, ignoring its TTL and how its names are written.
+func dnsRecordKey(domain string, dnsRecord DNSRecord) string {
+	value := dnsRecord.Value
+
+	switch strings.ToUpper(dnsRecord.Type) {
+	case "CNAME", "MX", "NS", "PTR", "SRV":
+		value = relativeDNSName(value, domain)
+	}
+
+	dnsRecord.Name = relativeDNSName(dnsRecord.Name, domain)
+	dnsRecord.TTL = 0
+	dnsRecord.Value = value
+	rawDNSRecordData := dnsRecord.translate()
+
+	return rawDNSRecordData.Name + " " + rawDNSRecordData.Type + " " + rawDNSRecordData.Value
+}
@@ -0,0 +1,43 @@
+package directadmin
+
+import "testing"
+
+func TestPlanDNSRecords(t *testing.T) {
+	current := []DNSRecord{
+		{Name: "example.com.", Type: "NS", Value: "ns1.example.net."},
+		{Name: "example.com.", Type: "A", TTL: 3600, Value: "192.0.2.10"},
+		{Name: "www", Type: "CNAME", Value: "example.com."},
+		{Name: "api", Type: "A", Value: "192.0.2.40"},
+		{Name: "old", Type: "A", Value: "192.0.2.99"},
+	}
+
+	desired := []DNSRecord{
+		{Name: "example.com.", Type: "A", TTL: 300, Value: "192.0.2.10"},
+		{Name: "WWW", Type: "CNAME", Value: "@"},
+		{Name: "api", Type: "A", Value: "192.0.2.41"},
+		{Name: "new", Type: "A", Value: "192.0.2.50"},
+	}
+
+	plan := planDNSRecords("example.com", current, desired, DNSSyncOptions{ProtectNS: true, ProtectSOA: true})
+
+	if len(plan.Adds) != 1 || plan.Adds[0].Name != "new" {
+		t.Errorf("unexpected adds: %+v", plan.Adds)
+	}
+
+	if len(plan.Edits) != 2 || plan.Edits[0].To.TTL != 300 || plan.Edits[1].From.Value != "192.0.2.40" {
+		t.Errorf("unexpected edits: %+v", plan.Edits)
+	}
+
+	if len(plan.Deletes) != 1 || plan.Deletes[0].Name != "old" {
+		t.Errorf("expected only the stale record to be deleted, keeping NS: %+v", plan.Deletes)
+	}
+
+	plan = planDNSRecords("example.com", current, desired, DNSSyncOptions{KeepExtra: true})
+	if len(plan.Adds) != 2 || len(plan.Edits) != 1 || len(plan.Deletes) != 0 {
+		t.Errorf("unexpected plan when keeping extra records: %+v", plan)
+	}
+
+	if plan = planDNSRecords("example.com", current, current, DNSSyncOptions{}); !plan.Empty() {
+		t.Errorf("expected an empty plan, got:\n%v", plan)
+	}
+}
@@ -0,0 +1,89 @@
+package directadmin
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDNSRecordTranslation(t *testing.T) {
+	longTXT := "v=DKIM1; k=rsa; p=" + strings.Repeat("A", 400)
+
+	tests := []struct {
+		raw      rawDNSRecord
+		expected DNSRecord
+	}{
+		{
+			raw:      rawDNSRecord{Name: "www", TTL: "3600", Type: "A", Value: "192.0.2.1"},
+			expected: DNSRecord{Name: "www", TTL: 3600, Type: "A", Value: "192.0.2.1"},
+		},
+		{
+			raw:      rawDNSRecord{Name: "example.com.", Type: "MX", Value: "10 mail.example.com."},
+			expected: DNSRecord{Name: "example.com.", Priority: 10, Type: "MX", Value: "mail.example.com."},
+		},
+		{
+			raw:      rawDNSRecord{Name: "_sip._tcp", TTL: "300", Type: "SRV", Value: "10 60 5060 sip.example.com."},
+			expected: DNSRecord{Name: "_sip._tcp", Port: 5060, Priority: 10, TTL: 300, Type: "SRV", Value: "sip.example.com.", Weight: 60},
+		},
+		{
+			raw:      rawDNSRecord{Name: "example.com.", Type: "CAA", Value: `128 iodef "mailto:security@example.com"`},
+			expected: DNSRecord{Flags: 128, Name: "example.com.", Tag: "iodef", Type: "CAA", Value: "mailto:security@example.com"},
+		},
+		{
+			raw:      rawDNSRecord{Name: "example.com.", Type: "TXT", Value: `"v=spf1 a mx include:a&b=c \"quoted\" ~all"`},
+			expected: DNSRecord{Name: "example.com.", Type: "TXT", Value: `v=spf1 a mx include:a&b=c "quoted" ~all`},
+		},
+		{
+			raw:      rawDNSRecord{Name: "x._domainkey", Type: "TXT", Value: `"` + longTXT[:255] + `" "` + longTXT[255:] + `"`},
+			expected: DNSRecord{Name: "x._domainkey", Type: "TXT", Value: longTXT},
+		},
+	}
+
+	for _, test := range tests {
+		dnsRecord := test.raw.translate()
+		if dnsRecord != test.expected {
+			t.Errorf("%v: expected %+v, got %+v", test.raw.Type, test.expected, dnsRecord)
+		}
+
+		if convertedRecord := dnsRecord.translate(); convertedRecord != test.raw {
+			t.Errorf("%v: expected %+v, got %+v", test.raw.Type, test.raw, convertedRecord)
+		}
+	}
+}
+
+func TestDNSRecordSelector(t *testing.T) {
+	dnsRecord := DNSRecord{Name: "example.com.", Type: "TXT", Value: "a=b&c=d"}
+
+	selector, err := url.ParseQuery(dnsRecord.selector())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if selector.Get("name") != "example.com." || selector.Get("value") != `"a=b&c=d"` {
+		t.Fatalf("unexpected selector: %v", selector)
+	}
+}
+
+func TestDNSRecordSelectorUsesStoredValue(t *testing.T) {
+	// DA stored the TXT record split at a different point and the MX record with extra spacing than we'd produce.
+	zone := rawDNSZone{DNSRecords: []rawDNSRecord{
+		{Name: "example.com.", Type: "TXT", Value: `"v=spf1 " "a mx ~all"`},
+		{Name: "example.com.", Type: "MX", Value: "10  mail.example.com."},
+	}}
+
+	dnsRecords, err := zone.translateRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, dnsRecord := range dnsRecords {
+		selector, err := url.ParseQuery(dnsRecord.selector())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if value := selector.Get("value"); value != zone.DNSRecords[i].Value {
+			t.Errorf("%v: expected the stored value %q to select the record, got %q", dnsRecord.Type, zone.DNSRecords[i].Value, value)
+		}
+	}
+}
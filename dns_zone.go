@@ -0,0 +1,398 @@
+package directadmin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+)
+
+// defaultZoneTTL is DA's default TTL, used in exports when the zone doesn't report its own.
+const defaultZoneTTL = 14400
+
+const (
+	// ZoneImportMerge adds and updates the imported records, leaving any other records in place.
+	ZoneImportMerge = ZoneImportMode("merge")
+	// ZoneImportReplace makes the zone match the imported records exactly.
+	ZoneImportReplace = ZoneImportMode("replace")
+)
+
+type ZoneImportMode string
+
+// ExportZone (user) returns the given domain's SOA, default TTL and DNS records as RFC 1035 zone file text.
+func (c *UserContext) ExportZone(domain string) (string, error) {
+	rawZone, err := c.getRawDNSZone(domain)
+	if err != nil {
+		return "", err
+	}
+
+	dnsRecords, err := rawZone.translateRecords()
+	if err != nil {
+		return "", err
+	}
+
+	return formatZone(domain, rawZone.translateSOA(), dnsRecords)
+}
+
+// ImportZone (user) parses the given zone file and applies it to the domain using the given mode, returning the plan
+// that was applied. SOA records are ignored, as DA manages them itself. Unless the zone file contains apex NS records of
+// its own, the domain's NS records are kept. NS records delegating subdomains don't count.
+func (c *UserContext) ImportZone(domain string, zone io.Reader, mode ZoneImportMode) (*DNSPlan, error) {
+	if mode != ZoneImportMerge && mode != ZoneImportReplace {
+		return nil, fmt.Errorf("invalid zone import mode: %v", mode)
+	}
+
+	imported, err := parseZone(zone, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse zone: %w", err)
+	}
+
+	return c.SyncDNSRecords(domain, imported, zoneImportOptions(domain, imported, mode))
+}
+
+// zoneImportOptions returns the sync options for importing the given records with the given mode.
+func zoneImportOptions(domain string, imported []DNSRecord, mode ZoneImportMode) DNSSyncOptions {
+	opts := DNSSyncOptions{
+		KeepExtra:  mode == ZoneImportMerge,
+		ProtectNS:  true,
+		ProtectSOA: true,
+	}
+
+	for _, dnsRecord := range imported {
+		if dnsRecord.Type == "NS" && relativeDNSName(dnsRecord.Name, domain) == "@" {
+			opts.ProtectNS = false
+			break
+		}
+	}
+
+	return opts
+}
+
+// formatZone renders the given SOA and records as zone file text, with names relative to the domain. The $TTL is the
+// zone's default TTL, or DA's default if it's unknown. The SOA is left out if DA didn't report its name servers.
+func formatZone(domain string, soa ZoneSOA, dnsRecords []DNSRecord) (string, error) {
+	var builder strings.Builder
+
+	origin := strings.TrimSuffix(domain, ".") + "."
+
+	defaultTTL := soa.DefaultTTL
+	if defaultTTL <= 0 {
+		defaultTTL = defaultZoneTTL
+	}
+
+	builder.WriteString("$ORIGIN " + origin + "\n")
+	builder.WriteString("$TTL " + strconv.Itoa(defaultTTL) + "\n")
+
+	writer := tabwriter.NewWriter(&builder, 0, 8, 1, ' ', 0)
+
+	if soa.PrimaryNS != "" && soa.Hostmaster != "" {
+		// DA reports both as fully qualified names, and may report the hostmaster as an email address, which zone
+		// files write with a dot instead of the @.
+		primaryNS := strings.TrimSuffix(soa.PrimaryNS, ".") + "."
+		hostmaster := strings.TrimSuffix(strings.Replace(soa.Hostmaster, "@", ".", 1), ".") + "."
+
+		if _, err := fmt.Fprintf(writer, "@\t\tIN\tSOA\t%s %s %d %d %d %d %d\n", primaryNS, hostmaster, soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.Minimum); err != nil {
+			return "", err
+		}
+	}
+
+	for _, dnsRecord := range dnsRecords {
+		if strings.EqualFold(dnsRecord.Type, "SOA") {
+			continue
+		}
+
+		rawDNSRecordData := dnsRecord.translate()
+
+		if _, err := fmt.Fprintf(writer, "%s\t%s\tIN\t%s\t%s\n", relativeDNSName(dnsRecord.Name, domain), rawDNSRecordData.TTL, rawDNSRecordData.Type, rawDNSRecordData.Value); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+// parseZone parses RFC 1035 zone file text into records with DA-style names: relative names for records inside the
+// zone, and the fully qualified domain for the apex. $ORIGIN, $TTL, "@", blank owners, and parenthesised multi-line
+// records are supported. SOA records are skipped.
+func parseZone(zone io.Reader, domain string) ([]DNSRecord, error) {
+	var dnsRecords []DNSRecord
+
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	origin := domain + "."
+	defaultTTL := 0
+	previousOwner := origin
+
+	lines, err := readZoneLines(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		tokens := line.tokens
+
+		switch strings.ToUpper(tokens[0]) {
+		case "$INCLUDE":
+			return nil, fmt.Errorf("line %d: $INCLUDE is not supported", line.number)
+		case "$ORIGIN":
+			if len(tokens) != 2 {
+				return nil, fmt.Errorf("line %d: invalid $ORIGIN", line.number)
+			}
+
+			origin = qualifyZoneName(tokens[1], origin)
+
+			continue
+		case "$TTL":
+			if len(tokens) != 2 {
+				return nil, fmt.Errorf("line %d: invalid $TTL", line.number)
+			}
+
+			if defaultTTL, err = parseZoneTTL(tokens[1]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.number, err)
+			}
+
+			continue
+		}
+
+		owner := previousOwner
+		if !line.inheritsOwner {
+			owner = qualifyZoneName(tokens[0], origin)
+			tokens = tokens[1:]
+		}
+
+		previousOwner = owner
+		ttl := defaultTTL
+
+		// The TTL and class can appear in either order before the type.
+		for len(tokens) > 0 {
+			if strings.EqualFold(tokens[0], "IN") {
+				tokens = tokens[1:]
+			} else if parsedTTL, ttlErr := parseZoneTTL(tokens[0]); ttlErr == nil && unicode.IsDigit(rune(tokens[0][0])) {
+				ttl = parsedTTL
+				tokens = tokens[1:]
+			} else {
+				break
+			}
+		}
+
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("line %d: missing record type or data", line.number)
+		}
+
+		recordType := strings.ToUpper(tokens[0])
+		rdata := tokens[1:]
+
+		if recordType == "SOA" {
+			continue
+		}
+
+		name := relativeDNSName(owner, domain)
+		if strings.HasSuffix(name, ".") {
+			return nil, fmt.Errorf("line %d: %v is outside of the %v zone", line.number, owner, domain)
+		}
+
+		if name == "@" {
+			name = domain + "."
+		}
+
+		// Qualify relative hostnames in the record data against the current origin.
+		switch recordType {
+		case "CNAME", "NS", "PTR":
+			rdata[0] = qualifyZoneName(rdata[0], origin)
+		case "MX":
+			if len(rdata) != 2 {
+				return nil, fmt.Errorf("line %d: invalid MX record", line.number)
+			}
+
+			rdata[1] = qualifyZoneName(rdata[1], origin)
+		case "SRV":
+			if len(rdata) != 4 {
+				return nil, fmt.Errorf("line %d: invalid SRV record", line.number)
+			}
+
+			rdata[3] = qualifyZoneName(rdata[3], origin)
+		case "CAA":
+			if len(rdata) < 3 {
+				return nil, fmt.Errorf("line %d: invalid CAA record", line.number)
+			}
+		}
+
+		rawDNSRecordData := rawDNSRecord{
+			Name:  name,
+			Type:  recordType,
+			Value: strings.Join(rdata, " "),
+		}
+
+		if ttl > 0 {
+			rawDNSRecordData.TTL = strconv.Itoa(ttl)
+		}
+
+		dnsRecords = append(dnsRecords, rawDNSRecordData.translate())
+	}
+
+	if len(dnsRecords) == 0 {
+		return nil, errors.New("no dns records were found")
+	}
+
+	return dnsRecords, nil
+}
+
+// parseZoneTTL parses a TTL in seconds, or with BIND's unit suffixes, e.g. "1h30m".
+func parseZoneTTL(value string) (int, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return seconds, nil
+	}
+
+	units := map[rune]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total, current := 0, 0
+	hasDigits := false
+
+	for _, char := range strings.ToLower(value) {
+		if unicode.IsDigit(char) {
+			current = current*10 + int(char-'0')
+			hasDigits = true
+
+			continue
+		}
+
+		multiplier, ok := units[char]
+		if !ok || !hasDigits {
+			return 0, fmt.Errorf("invalid TTL: %v", value)
+		}
+
+		total += current * multiplier
+		current = 0
+		hasDigits = false
+	}
+
+	if hasDigits {
+		return 0, fmt.Errorf("invalid TTL: %v", value)
+	}
+
+	return total, nil
+}
+
+// qualifyZoneName returns the fully qualified form of the given name.
+func qualifyZoneName(name string, origin string) string {
+	name = strings.ToLower(name)
+
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return name
+	}
+
+	return name + "." + origin
+}
+
+type zoneLine struct {
+	// inheritsOwner is set when the line starts with whitespace, meaning it uses the previous record's owner.
+	inheritsOwner bool
+	number        int
+	tokens        []string
+}
+
+// readZoneLines splits zone file text into logical lines of tokens, stripping comments and joining parenthesised
+// records. Quoted strings are kept as a single token, including their quotes.
+func readZoneLines(zone io.Reader) ([]zoneLine, error) {
+	var lines []zoneLine
+	var current *zoneLine
+
+	depth := 0
+	lineNumber := 0
+	scanner := bufio.NewScanner(zone)
+
+	for scanner.Scan() {
+		lineNumber++
+		text := scanner.Text()
+
+		if current == nil {
+			current = &zoneLine{
+				inheritsOwner: len(text) > 0 && (text[0] == ' ' || text[0] == '\t'),
+				number:        lineNumber,
+			}
+		}
+
+		var token strings.Builder
+		inQuotes := false
+
+		flush := func() {
+			if token.Len() > 0 {
+				current.tokens = append(current.tokens, token.String())
+				token.Reset()
+			}
+		}
+
+	characters:
+		for i := 0; i < len(text); i++ {
+			char := text[i]
+
+			switch {
+			case inQuotes:
+				token.WriteByte(char)
+
+				if char == '\\' && i+1 < len(text) {
+					i++
+					token.WriteByte(text[i])
+				} else if char == '"' {
+					inQuotes = false
+					flush()
+				}
+			case char == '"':
+				flush()
+				inQuotes = true
+				token.WriteByte(char)
+			case char == ';':
+				break characters
+			case char == '(':
+				flush()
+				depth++
+			case char == ')':
+				flush()
+				depth--
+
+				if depth < 0 {
+					return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNumber)
+				}
+			case char == ' ' || char == '\t':
+				flush()
+			default:
+				token.WriteByte(char)
+			}
+		}
+
+		if inQuotes {
+			return nil, fmt.Errorf("line %d: unterminated quoted string", lineNumber)
+		}
+
+		flush()
+
+		if depth > 0 {
+			continue
+		}
+
+		if len(current.tokens) > 0 {
+			lines = append(lines, *current)
+		}
+
+		current = nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if depth > 0 {
+		return nil, errors.New("unbalanced parentheses at end of zone")
+	}
+
+	return lines, nil
+}
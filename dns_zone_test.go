@@ -0,0 +1,174 @@
+package directadmin
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func TestZoneExportImport(t *testing.T) {
+	zoneFile, err := os.Open("testdata/example.com.zone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zoneFile.Close()
+
+	dnsRecords, err := parseZone(zoneFile, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dnsRecords) != 14 {
+		t.Fatalf("expected 14 records (SOA skipped), got %d: %+v", len(dnsRecords), dnsRecords)
+	}
+
+	soa := ZoneSOA{
+		DefaultTTL: 3600,
+		Expire:     1209600,
+		Hostmaster: "hostmaster@example.com",
+		Minimum:    300,
+		PrimaryNS:  "ns1.example.net",
+		Refresh:    3600,
+		Retry:      1800,
+		Serial:     2025101801,
+	}
+
+	exported, err := formatZone("example.com", soa, dnsRecords)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *updateGolden {
+		if err = os.WriteFile("testdata/example.com.golden", []byte(exported), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	golden, err := os.ReadFile("testdata/example.com.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exported != string(golden) {
+		t.Fatalf("exported zone doesn't match golden file:\n%s", exported)
+	}
+
+	// Re-importing the export must produce the same records.
+	reimported, err := parseZone(strings.NewReader(exported), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reimported) != len(dnsRecords) {
+		t.Fatalf("expected %d records after re-import, got %d", len(dnsRecords), len(reimported))
+	}
+
+	for i := range dnsRecords {
+		if reimported[i] != dnsRecords[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, dnsRecords[i], reimported[i])
+		}
+	}
+}
+
+func TestDiffZoneImport(t *testing.T) {
+	current := []DNSRecord{
+		{Name: "example.com.", Type: "NS", Value: "ns1.example.net."},
+		{Name: "example.com.", Type: "A", TTL: 3600, Value: "192.0.2.10"},
+		{Name: "www", Type: "CNAME", Value: "example.com."},
+		{Name: "old", Type: "A", Value: "192.0.2.99"},
+		{Name: "sub", Type: "NS", Value: "ns1.other.tld."},
+	}
+
+	unchanged := []DNSRecord{
+		{Name: "example.com.", Type: "A", TTL: 3600, Value: "192.0.2.10"},
+		{Name: "www", Type: "CNAME", Value: "example.com."},
+		{Name: "old", Type: "A", Value: "192.0.2.99"},
+	}
+
+	tests := []struct {
+		expectedAdds    []string
+		expectedDeletes []string
+		expectedEdits   int
+		imported        []DNSRecord
+		mode            ZoneImportMode
+		name            string
+	}{
+		{
+			expectedAdds: []string{"new"},
+			imported: []DNSRecord{
+				{Name: "example.com.", Type: "A", TTL: 300, Value: "192.0.2.10"},
+				{Name: "WWW", Type: "CNAME", Value: "@"},
+				{Name: "new", Type: "A", Value: "192.0.2.50"},
+			},
+			expectedEdits: 1,
+			mode:          ZoneImportMerge,
+			name:          "merge",
+		},
+		{
+			expectedEdits: 1,
+			imported:      []DNSRecord{{Name: "www", Type: "CNAME", Value: "new.example.net."}},
+			mode:          ZoneImportMerge,
+			name:          "merge replaces a CNAME value",
+		},
+		{
+			expectedAdds: []string{"old"},
+			imported:     []DNSRecord{{Name: "old", Type: "A", Value: "192.0.2.100"}},
+			mode:         ZoneImportMerge,
+			name:         "merge adds to an A record set",
+		},
+		{
+			expectedAdds:    []string{"new"},
+			expectedDeletes: []string{"old"},
+			expectedEdits:   1,
+			imported: []DNSRecord{
+				{Name: "example.com.", Type: "A", TTL: 300, Value: "192.0.2.10"},
+				{Name: "WWW", Type: "CNAME", Value: "@"},
+				{Name: "new", Type: "A", Value: "192.0.2.50"},
+			},
+			mode: ZoneImportReplace,
+			name: "replace keeps NS records",
+		},
+		{
+			expectedAdds: []string{"other"},
+			imported:     append(unchanged, DNSRecord{Name: "other", Type: "NS", Value: "ns1.other.tld."}),
+			mode:         ZoneImportReplace,
+			name:         "replace with only delegated subdomains keeps NS records",
+		},
+		{
+			expectedDeletes: []string{"sub"},
+			expectedEdits:   1,
+			imported:        append(unchanged, DNSRecord{Name: "@", Type: "NS", Value: "ns2.example.net."}),
+			mode:            ZoneImportReplace,
+			name:            "replace with apex NS replaces NS records",
+		},
+	}
+
+	for _, test := range tests {
+		plan := planDNSRecords("example.com", current, test.imported, zoneImportOptions("example.com", test.imported, test.mode))
+
+		var adds, deletes []string
+
+		for _, dnsRecord := range plan.Adds {
+			adds = append(adds, dnsRecord.Name)
+		}
+
+		for _, dnsRecord := range plan.Deletes {
+			deletes = append(deletes, dnsRecord.Name)
+		}
+
+		if strings.Join(adds, ",") != strings.Join(test.expectedAdds, ",") {
+			t.Errorf("%v: expected adds %v, got %v", test.name, test.expectedAdds, adds)
+		}
+
+		if strings.Join(deletes, ",") != strings.Join(test.expectedDeletes, ",") {
+			t.Errorf("%v: expected deletes %v, got %v", test.name, test.expectedDeletes, deletes)
+		}
+
+		if len(plan.Edits) != test.expectedEdits {
+			t.Errorf("%v: expected %d edits, got %+v", test.name, test.expectedEdits, plan.Edits)
+		}
+	}
+}
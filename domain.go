@@ -12,26 +12,27 @@ import (
 )
 
 type Domain struct {
-	Active             bool     `json:"active" yaml:"active"`
-	BandwidthQuota     int      `json:"bandwidthQuota" yaml:"bandwidthQuota"`
-	BandwidthUsage     int      `json:"bandwidthUsage" yaml:"bandwidthUsage"`
-	CGIEnabled         bool     `json:"cgiEnabled" yaml:"cgiEnabled"`
-	DefaultDomain      bool     `json:"defaultDomain" yaml:"defaultDomain"`
-	DiskQuota          int      `json:"diskQuota" yaml:"diskQuota"`
-	DiskUsage          int      `json:"diskUsage" yaml:"diskUsage"`
-	Domain             string   `json:"domain" yaml:"domain"`
-	IPAddresses        []string `json:"ipAddresses" yaml:"ipAddresses"`
-	ModSecurityEnabled bool     `json:"modSecurityEnabled" yaml:"modSecurityEnabled"`
-	OpenBaseDirEnabled bool     `json:"openBaseDirEnabled" yaml:"openBaseDirEnabled"`
-	PHPEnabled         bool     `json:"phpEnabled" yaml:"phpEnabled"`
-	PHPSelectorEnabled bool     `json:"phpSelectorEnabled" yaml:"phpSelectorEnabled"`
-	PHPVersion         string   `json:"phpVersion" yaml:"phpVersion"`
-	SafeMode           bool     `json:"safeMode" yaml:"safeMode"`
-	SSLEnabled         bool     `json:"sslEnabled" yaml:"sslEnabled"`
-	Subdomains         []string `json:"subdomains" yaml:"subdomains"`
-	SubdomainUsage     int      `json:"subdomainUsage" yaml:"subdomainUsage"`
-	Suspended          bool     `json:"suspended" yaml:"suspended"`
-	Username           string   `json:"username" yaml:"username"`
+	Active             bool            `json:"active" yaml:"active"`
+	BandwidthQuota     int             `json:"bandwidthQuota" yaml:"bandwidthQuota"`
+	BandwidthUsage     int             `json:"bandwidthUsage" yaml:"bandwidthUsage"`
+	CGIEnabled         bool            `json:"cgiEnabled" yaml:"cgiEnabled"`
+	DefaultDomain      bool            `json:"defaultDomain" yaml:"defaultDomain"`
+	DiskQuota          int             `json:"diskQuota" yaml:"diskQuota"`
+	DiskUsage          int             `json:"diskUsage" yaml:"diskUsage"`
+	Domain             string          `json:"domain" yaml:"domain"`
+	IPAddresses        []string        `json:"ipAddresses" yaml:"ipAddresses"`
+	ModSecurityEnabled bool            `json:"modSecurityEnabled" yaml:"modSecurityEnabled"`
+	OpenBaseDirEnabled bool            `json:"openBaseDirEnabled" yaml:"openBaseDirEnabled"`
+	PHPEnabled         bool            `json:"phpEnabled" yaml:"phpEnabled"`
+	PHPSelectorEnabled bool            `json:"phpSelectorEnabled" yaml:"phpSelectorEnabled"`
+	PHPVersion         string          `json:"phpVersion" yaml:"phpVersion"`
+	Pointers           []DomainPointer `json:"pointers" yaml:"pointers"`
+	SafeMode           bool            `json:"safeMode" yaml:"safeMode"`
+	SSLEnabled         bool            `json:"sslEnabled" yaml:"sslEnabled"`
+	Subdomains         []string        `json:"subdomains" yaml:"subdomains"`
+	SubdomainUsage     int             `json:"subdomainUsage" yaml:"subdomainUsage"`
+	Suspended          bool            `json:"suspended" yaml:"suspended"`
+	Username           string          `json:"username" yaml:"username"`
 }
 
 // AddDomainIP (user) adds an additional IP to a domain.
@@ -153,7 +154,8 @@ func (c *UserContext) DeleteDomains(deleteData bool, domains ...string) error {
 	return nil
 }
 
-// GetDomain (user) returns the single specified domain.
+// GetDomain (user) returns the single specified domain. Its pointers are nil if they couldn't be fetched, e.g. because
+// pointers are disabled for the user.
 func (c *UserContext) GetDomain(domainName string) (Domain, error) {
 	// check if domain is in cache
 	if c.api.cacheEnabled {
@@ -185,10 +187,18 @@ func (c *UserContext) GetDomain(domainName string) (Domain, error) {
 		return Domain{}, err
 	}
 
-	return rawDomainData.translate(), nil
+	domain := rawDomainData.translate()
+
+	// Pointers are supplementary, so failing to fetch them shouldn't fail the whole domain.
+	if pointers, err := c.GetDomainPointers(domainName); err == nil {
+		domain.Pointers = pointers
+	}
+
+	return domain, nil
 }
 
-// GetDomains (user) returns the session user's domains.
+// GetDomains (user) returns the session user's domains. As with GetDomain, a domain's pointers are nil if they couldn't
+// be fetched.
 func (c *UserContext) GetDomains() ([]Domain, error) {
 	var domains []Domain
 	var rawDomains map[string]rawDomain
@@ -249,8 +259,14 @@ func (c *UserContext) GetDomains() ([]Domain, error) {
 				rawDomainData.Subdomains = []string{}
 			}
 
+			domain := rawDomainData.translate()
+
+			if pointers, err := c.GetDomainPointers(domain.Domain); err == nil {
+				domain.Pointers = pointers
+			}
+
 			mu.Lock()
-			domains = append(domains, rawDomainData.translate())
+			domains = append(domains, domain)
 			mu.Unlock()
 
 			// Cache domain.
@@ -259,7 +275,7 @@ func (c *UserContext) GetDomains() ([]Domain, error) {
 					c.api.cache.domainsMutex.Lock()
 					c.api.cache.domains[domainToCache.Domain] = domainToCache
 					c.api.cache.domainsMutex.Unlock()
-				}(rawDomainData.translate())
+				}(domain)
 			}
 		}(domainToProcess)
 	}
@@ -0,0 +1,122 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cast"
+)
+
+// errorPageCodes are the status codes DA allows custom error pages for.
+var errorPageCodes = []int{
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusInternalServerError,
+}
+
+type ErrorPage struct {
+	// Code is one of 401, 403, 404 or 500.
+	Code    int    `json:"code" yaml:"code"`
+	Content string `json:"content" yaml:"content"`
+}
+
+// Validate checks the error page locally.
+func (p *ErrorPage) Validate() error {
+	for _, code := range errorPageCodes {
+		if p.Code == code {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("custom error pages aren't supported for status code %d", p.Code)
+}
+
+// DeleteErrorPages (user) removes the custom error pages for the given status codes, restoring the server defaults.
+func (c *UserContext) DeleteErrorPages(domain string, codes ...int) error {
+	var response apiGenericResponse
+
+	if len(codes) == 0 {
+		return errors.New("no status codes provided")
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+
+	for index, code := range codes {
+		page := ErrorPage{Code: code}
+		if err := page.Validate(); err != nil {
+			return err
+		}
+
+		body.Set("select"+cast.ToString(index), strconv.Itoa(code))
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_CUSTOM_ERROR?action=delete", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Pages Deleted" {
+		return fmt.Errorf("failed to delete error pages: %v", response.Result)
+	}
+
+	return nil
+}
+
+// GetErrorPages (user) returns the given domain's custom error pages, sorted by status code. Status codes using the
+// server defaults aren't included.
+func (c *UserContext) GetErrorPages(domain string) ([]ErrorPage, error) {
+	// DA returns a map of status code to page content.
+	var rawPages map[string]string
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_CUSTOM_ERROR?domain="+domain, nil, &rawPages); err != nil {
+		return nil, err
+	}
+
+	pages := make([]ErrorPage, 0, len(rawPages))
+
+	for code, content := range rawPages {
+		if content == "" {
+			continue
+		}
+
+		pages = append(pages, ErrorPage{
+			Code:    cast.ToInt(code),
+			Content: content,
+		})
+	}
+
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].Code < pages[j].Code
+	})
+
+	return pages, nil
+}
+
+// SetErrorPage (user) creates or overwrites the given domain's custom error page for the page's status code.
+func (c *UserContext) SetErrorPage(domain string, page ErrorPage) error {
+	var response apiGenericResponse
+
+	if err := page.Validate(); err != nil {
+		return err
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("page", strconv.Itoa(page.Code))
+	body.Set("text", page.Content)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_CUSTOM_ERROR?action=save", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Page Saved" {
+		return fmt.Errorf("failed to set error page: %v", response.Result)
+	}
+
+	return nil
+}
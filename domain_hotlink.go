@@ -0,0 +1,137 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type (
+	HotlinkProtection struct {
+		// AllowBlankReferrer lets requests without a referrer through, e.g. direct visits and privacy-focused browsers.
+		AllowBlankReferrer bool `json:"allowBlankReferrer" yaml:"allowBlankReferrer"`
+		// AllowedReferrers are the hosts allowed to embed protected files. The domain itself is always allowed.
+		AllowedReferrers []string `json:"allowedReferrers" yaml:"allowedReferrers"`
+		Enabled          bool     `json:"enabled" yaml:"enabled"`
+		// Extensions are the protected file extensions, without leading dots, e.g. "jpg".
+		Extensions []string `json:"extensions" yaml:"extensions"`
+		// RedirectURL is where blocked requests are sent. If empty, blocked requests get a 403 response.
+		RedirectURL string `json:"redirectURL" yaml:"redirectURL"`
+	}
+
+	rawHotlinkProtection struct {
+		AllowBlankReferrer string `json:"allow_blank"`
+		AllowedReferrers   string `json:"urls"`
+		Enabled            string `json:"enabled"`
+		Extensions         string `json:"files"`
+		RedirectURL        string `json:"redirect_url"`
+	}
+)
+
+// DeleteHotlinkProtection (user) disables hotlink protection for the given domain and clears its settings.
+func (c *UserContext) DeleteHotlinkProtection(domain string) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("domain", domain)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_HOTLINK?action=delete", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Hotlink Protection Removed" {
+		return fmt.Errorf("failed to delete hotlink protection: %v", response.Result)
+	}
+
+	return nil
+}
+
+// GetHotlinkProtection (user) returns the given domain's hotlink protection settings.
+func (c *UserContext) GetHotlinkProtection(domain string) (*HotlinkProtection, error) {
+	var rawProtection rawHotlinkProtection
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_HOTLINK?domain="+domain, nil, &rawProtection); err != nil {
+		return nil, err
+	}
+
+	protection := rawProtection.translate()
+
+	return &protection, nil
+}
+
+// SetHotlinkProtection (user) saves the given domain's hotlink protection settings.
+func (c *UserContext) SetHotlinkProtection(domain string, protection HotlinkProtection) error {
+	var response apiGenericResponse
+
+	if protection.Enabled && len(protection.Extensions) == 0 {
+		return errors.New("at least one extension is required when hotlink protection is enabled")
+	}
+
+	if protection.RedirectURL != "" {
+		redirectURL, err := url.Parse(protection.RedirectURL)
+		if err != nil || redirectURL.Scheme == "" || redirectURL.Host == "" {
+			return fmt.Errorf("redirect URL must be an absolute URL: %v", protection.RedirectURL)
+		}
+	}
+
+	rawProtection := protection.translate()
+
+	body := rawProtection.formValues()
+	body.Set("domain", domain)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_HOTLINK?action=save", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Hotlink Protection Saved" {
+		return fmt.Errorf("failed to set hotlink protection: %v", response.Result)
+	}
+
+	return nil
+}
+
+func (p *HotlinkProtection) translate() rawHotlinkProtection {
+	extensions := make([]string, 0, len(p.Extensions))
+	for _, extension := range p.Extensions {
+		extensions = append(extensions, strings.TrimPrefix(strings.ToLower(extension), "."))
+	}
+
+	return rawHotlinkProtection{
+		AllowBlankReferrer: reverseParseYesNo(p.AllowBlankReferrer),
+		AllowedReferrers:   strings.Join(p.AllowedReferrers, "\n"),
+		Enabled:            reverseParseYesNo(p.Enabled),
+		Extensions:         strings.Join(extensions, ","),
+		RedirectURL:        p.RedirectURL,
+	}
+}
+
+func (p *rawHotlinkProtection) formValues() url.Values {
+	body := url.Values{}
+	body.Set("allow_blank", p.AllowBlankReferrer)
+	body.Set("enabled", p.Enabled)
+	body.Set("files", p.Extensions)
+	body.Set("redirect_url", p.RedirectURL)
+	body.Set("urls", p.AllowedReferrers)
+
+	return body
+}
+
+func (p *rawHotlinkProtection) translate() HotlinkProtection {
+	protection := HotlinkProtection{
+		AllowBlankReferrer: parseOnOff(p.AllowBlankReferrer),
+		AllowedReferrers:   strings.Fields(p.AllowedReferrers),
+		Enabled:            parseOnOff(p.Enabled),
+		Extensions:         []string{},
+		RedirectURL:        p.RedirectURL,
+	}
+
+	for _, extension := range strings.Split(p.Extensions, ",") {
+		if extension = strings.TrimSpace(extension); extension != "" {
+			protection.Extensions = append(protection.Extensions, extension)
+		}
+	}
+
+	return protection
+}
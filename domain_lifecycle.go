@@ -0,0 +1,277 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type (
+	// DomainRenameReport describes what DA changed when renaming a domain, based on the domain's state before and after.
+	DomainRenameReport struct {
+		// Details are DA's own description of the rename, one line per step.
+		Details   []string             `json:"details"`
+		DNS       DomainRenameDNSInfo  `json:"dns"`
+		Mail      DomainRenameMailInfo `json:"mail"`
+		NewDomain string               `json:"newDomain"`
+		OldDomain string               `json:"oldDomain"`
+		SSL       DomainRenameSSLInfo  `json:"ssl"`
+	}
+
+	DomainRenameDNSInfo struct {
+		ZoneRenamed bool `json:"zoneRenamed"`
+		// StaleRecords are records in the renamed zone that still point at the old domain.
+		StaleRecords []DNSRecord `json:"staleRecords"`
+	}
+
+	DomainRenameMailInfo struct {
+		// Missing are the usernames of email accounts that existed under the old domain but not the new one.
+		Missing []string `json:"missing"`
+		// Moved are the usernames of email accounts now under the new domain.
+		Moved []string `json:"moved"`
+	}
+
+	DomainRenameSSLInfo struct {
+		Enabled bool `json:"enabled"`
+		// ReissueRequired is set when SSL was enabled before the rename. DA keeps the existing certificate, which only
+		// covers the old hostnames.
+		ReissueRequired bool `json:"reissueRequired"`
+	}
+)
+
+// MoveDomainToUser (admin) moves the given domain, including its files, email and DNS zone, to another user.
+func (c *AdminContext) MoveDomainToUser(domain string, newOwner string) error {
+	var response apiGenericResponse
+
+	if domain == "" || newOwner == "" {
+		return errors.New("domain and new owner are required")
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("user", newOwner)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_MOVE_DOMAIN", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Domain Moved" {
+		return fmt.Errorf("failed to move domain: %v", response.Result)
+	}
+
+	// The cached copy belongs to the old owner.
+	if c.api.cacheEnabled {
+		c.api.cache.domainsMutex.Lock()
+		delete(c.api.cache.domains, domain)
+		c.api.cache.domainsMutex.Unlock()
+	}
+
+	return nil
+}
+
+// RenameDomain (user) renames the given domain. DA moves the domain's files, DNS zone, email accounts and SSL settings
+// to the new name; the returned report describes what changed.
+func (c *UserContext) RenameDomain(oldDomain string, newDomain string) (*DomainRenameReport, error) {
+	var response struct {
+		apiGenericResponse
+		Details string `json:"details"`
+	}
+
+	oldDomain = strings.ToLower(strings.TrimSuffix(oldDomain, "."))
+	newDomain = strings.ToLower(strings.TrimSuffix(newDomain, "."))
+
+	if oldDomain == "" || newDomain == "" {
+		return nil, errors.New("old and new domain are required")
+	}
+
+	if oldDomain == newDomain {
+		return nil, errors.New("new domain is the same as the old domain")
+	}
+
+	before, err := c.GetDomain(oldDomain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain: %w", err)
+	}
+
+	// GetEmailAccounts returns an error when there are none, which just means there's nothing to move.
+	oldEmailAccounts, _ := c.GetEmailAccounts(oldDomain)
+
+	body := url.Values{}
+	body.Set("new_domain", newDomain)
+	body.Set("old_domain", oldDomain)
+
+	if _, err = c.makeRequestOld(http.MethodPost, "API_CHANGE_DOMAIN", body, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Success != "Domain Changed" {
+		return nil, fmt.Errorf("failed to rename domain: %v", response.Result)
+	}
+
+	if c.api.cacheEnabled {
+		c.api.cache.domainsMutex.Lock()
+		delete(c.api.cache.domains, oldDomain)
+		c.api.cache.domainsMutex.Unlock()
+
+		c.api.cache.emailAccountsMutex.Lock()
+		delete(c.api.cache.emailAccounts, oldDomain)
+		c.api.cache.emailAccountsMutex.Unlock()
+	}
+
+	report := &DomainRenameReport{
+		Details:   splitResponseDetails(response.Details),
+		NewDomain: newDomain,
+		OldDomain: oldDomain,
+		SSL: DomainRenameSSLInfo{
+			ReissueRequired: before.SSLEnabled,
+		},
+	}
+
+	after, err := c.GetDomain(newDomain)
+	if err != nil {
+		return report, fmt.Errorf("domain was renamed, but failed to get the renamed domain: %w", err)
+	}
+
+	report.SSL.Enabled = after.SSLEnabled
+
+	if dnsRecords, dnsErr := c.GetDNSRecords(newDomain); dnsErr == nil {
+		report.DNS = DomainRenameDNSInfo{
+			StaleRecords: staleDNSRecords(dnsRecords, oldDomain, newDomain),
+			ZoneRenamed:  true,
+		}
+	}
+
+	newEmailAccounts, _ := c.GetEmailAccounts(newDomain)
+	report.Mail = diffRenamedEmailAccounts(oldEmailAccounts, newEmailAccounts)
+
+	return report, nil
+}
+
+// SuspendDomain (user) suspends the given domain, disabling its website and email.
+func (c *UserContext) SuspendDomain(domain string) error {
+	return c.setDomainSuspended(domain, true)
+}
+
+// UnsuspendDomain (user) unsuspends the given domain.
+func (c *UserContext) UnsuspendDomain(domain string) error {
+	return c.setDomainSuspended(domain, false)
+}
+
+func (c *UserContext) setDomainSuspended(domain string, suspended bool) error {
+	var response apiGenericResponse
+
+	action := "unsuspend"
+	if suspended {
+		action = "suspend"
+	}
+
+	body := url.Values{}
+	body.Set(action, "yes")
+	body.Set("select0", domain)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DOMAIN?action=select", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Success" {
+		return fmt.Errorf("failed to %v domain: %v", action, response.Result)
+	}
+
+	if c.api.cacheEnabled {
+		c.api.cache.domainsMutex.Lock()
+		if cachedDomain, ok := c.api.cache.domains[domain]; ok {
+			cachedDomain.Active = !suspended
+			cachedDomain.Suspended = suspended
+			c.api.cache.domains[domain] = cachedDomain
+		}
+		c.api.cache.domainsMutex.Unlock()
+	}
+
+	return nil
+}
+
+// diffRenamedEmailAccounts compares the email accounts from before and after a rename by username.
+func diffRenamedEmailAccounts(before []EmailAccount, after []EmailAccount) DomainRenameMailInfo {
+	info := DomainRenameMailInfo{
+		Missing: []string{},
+		Moved:   []string{},
+	}
+
+	moved := make(map[string]bool, len(after))
+	for _, emailAccount := range after {
+		moved[strings.ToLower(emailAccount.Username)] = true
+		info.Moved = append(info.Moved, emailAccount.Username)
+	}
+
+	for _, emailAccount := range before {
+		if !moved[strings.ToLower(emailAccount.Username)] {
+			info.Missing = append(info.Missing, emailAccount.Username)
+		}
+	}
+
+	sort.Strings(info.Missing)
+	sort.Strings(info.Moved)
+
+	return info
+}
+
+// splitResponseDetails splits DA's details text, which uses either newlines or <br> tags, into trimmed lines.
+func splitResponseDetails(details string) []string {
+	lines := []string{}
+
+	for _, line := range strings.Split(strings.ReplaceAll(details, "<br>", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// staleDNSRecords returns the records whose name or value still references the old domain.
+func staleDNSRecords(dnsRecords []DNSRecord, oldDomain string, newDomain string) []DNSRecord {
+	stale := []DNSRecord{}
+
+	for _, dnsRecord := range dnsRecords {
+		// Remove the new domain first, in case it contains the old one, e.g. example.com -> example.com.au.
+		text := strings.ToLower(dnsRecord.Name + " " + dnsRecord.Value)
+		if strings.Contains(newDomain, oldDomain) {
+			text = strings.ReplaceAll(text, newDomain, "")
+		}
+
+		if containsDomainName(text, oldDomain) {
+			stale = append(stale, dnsRecord)
+		}
+	}
+
+	return stale
+}
+
+// containsDomainName returns whether the text contains the domain as a whole name, so "example.com" matches
+// "mail.example.com" and "include:example.com", but not "myexample.com".
+func containsDomainName(text string, domain string) bool {
+	isHostnameChar := func(char byte) bool {
+		return char == '-' || char >= '0' && char <= '9' || char >= 'a' && char <= 'z'
+	}
+
+	for offset := 0; offset < len(text); {
+		index := strings.Index(text[offset:], domain)
+		if index == -1 {
+			return false
+		}
+
+		start := offset + index
+		end := start + len(domain)
+
+		if (start == 0 || !isHostnameChar(text[start-1])) && (end == len(text) || !isHostnameChar(text[end])) {
+			return true
+		}
+
+		offset = start + 1
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package directadmin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStaleDNSRecords(t *testing.T) {
+	dnsRecords := []DNSRecord{
+		{Name: "example.com.au.", Type: "A", Value: "192.0.2.1"},
+		{Name: "www", Type: "CNAME", Value: "example.com.au."},
+		{Name: "example.com.au.", Type: "MX", Priority: 10, Value: "mail.example.com."},
+		{Name: "example.com.au.", Type: "TXT", Value: "v=spf1 a mx include:example.com ~all"},
+		{Name: "shop", Type: "CNAME", Value: "myexample.com."},
+	}
+
+	expected := []DNSRecord{dnsRecords[2], dnsRecords[3]}
+
+	if stale := staleDNSRecords(dnsRecords, "example.com", "example.com.au"); !reflect.DeepEqual(stale, expected) {
+		t.Errorf("expected %+v, got %+v", expected, stale)
+	}
+}
+
+func TestDiffRenamedEmailAccounts(t *testing.T) {
+	before := []EmailAccount{{Username: "info"}, {Username: "Sales"}, {Username: "old"}}
+	after := []EmailAccount{{Username: "sales"}, {Username: "info"}}
+
+	expected := DomainRenameMailInfo{
+		Missing: []string{"old"},
+		Moved:   []string{"info", "sales"},
+	}
+
+	if info := diffRenamedEmailAccounts(before, after); !reflect.DeepEqual(info, expected) {
+		t.Errorf("expected %+v, got %+v", expected, info)
+	}
+}
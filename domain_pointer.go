@@ -0,0 +1,150 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	// DomainPointerAlias serves the target domain's site, email and DNS under the pointer's name.
+	DomainPointerAlias = DomainPointerType("alias")
+	// DomainPointerRedirect redirects the pointer's visitors to the target domain.
+	DomainPointerRedirect = DomainPointerType("redirect")
+)
+
+type (
+	DomainPointerType string
+
+	DomainPointer struct {
+		// Source is the pointer's domain name.
+		Source string `json:"source" yaml:"source"`
+		// Target is the domain the pointer points to.
+		Target string            `json:"target" yaml:"target"`
+		Type   DomainPointerType `json:"type" yaml:"type"`
+	}
+)
+
+// CreateDomainPointer (user) creates the given domain pointer.
+func (c *UserContext) CreateDomainPointer(pointer DomainPointer) error {
+	var response apiGenericResponse
+
+	body, err := pointer.formValues()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DOMAIN_POINTER?action=add", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Pointer Created" {
+		return fmt.Errorf("failed to create domain pointer: %v", response.Result)
+	}
+
+	// Pointers are cached with their target domain.
+	if c.api.cacheEnabled {
+		c.api.cache.domainsMutex.Lock()
+		delete(c.api.cache.domains, pointer.Target)
+		c.api.cache.domainsMutex.Unlock()
+	}
+
+	return nil
+}
+
+// DeleteDomainPointers (user) deletes the given pointers from the given domain.
+func (c *UserContext) DeleteDomainPointers(domain string, pointers ...string) error {
+	var response apiGenericResponse
+
+	if len(pointers) == 0 {
+		return errors.New("no domain pointers provided")
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+
+	for index, pointer := range pointers {
+		body.Set("select"+cast.ToString(index), pointer)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_DOMAIN_POINTER?action=delete", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Pointers Deleted" {
+		return fmt.Errorf("failed to delete domain pointers: %v", response.Result)
+	}
+
+	// Pointers are cached with their target domain.
+	if c.api.cacheEnabled {
+		c.api.cache.domainsMutex.Lock()
+		delete(c.api.cache.domains, domain)
+		c.api.cache.domainsMutex.Unlock()
+	}
+
+	return nil
+}
+
+// GetDomainPointers (user) returns the pointers for the given domain, sorted by source.
+func (c *UserContext) GetDomainPointers(domain string) ([]DomainPointer, error) {
+	// DA returns a map of pointer to either "alias" or "pointer".
+	var rawPointers map[string]string
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_DOMAIN_POINTER?domain="+domain, nil, &rawPointers); err != nil {
+		return nil, err
+	}
+
+	return translateDomainPointers(domain, rawPointers), nil
+}
+
+// formValues returns the fields DA's pointer form expects when creating the pointer.
+func (p *DomainPointer) formValues() (url.Values, error) {
+	if p.Source == "" || p.Target == "" {
+		return nil, errors.New("pointer source and target are required")
+	}
+
+	body := url.Values{}
+	body.Set("domain", p.Target)
+	body.Set("from", p.Source)
+
+	switch p.Type {
+	case DomainPointerAlias:
+		body.Set("alias", "yes")
+	case DomainPointerRedirect:
+		body.Set("alias", "no")
+	default:
+		return nil, fmt.Errorf("invalid domain pointer type: %q", p.Type)
+	}
+
+	return body, nil
+}
+
+// translateDomainPointers returns DA's pointer to type map as the given domain's pointers, sorted by source. DA calls
+// redirecting pointers "pointer", so anything that isn't an alias is treated as a redirect.
+func translateDomainPointers(domain string, rawPointers map[string]string) []DomainPointer {
+	pointers := make([]DomainPointer, 0, len(rawPointers))
+
+	for source, pointerType := range rawPointers {
+		pointer := DomainPointer{
+			Source: source,
+			Target: domain,
+			Type:   DomainPointerRedirect,
+		}
+
+		if pointerType == "alias" {
+			pointer.Type = DomainPointerAlias
+		}
+
+		pointers = append(pointers, pointer)
+	}
+
+	sort.Slice(pointers, func(i, j int) bool {
+		return pointers[i].Source < pointers[j].Source
+	})
+
+	return pointers
+}
@@ -0,0 +1,57 @@
+package directadmin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTranslateDomainPointers(t *testing.T) {
+	pointers := translateDomainPointers("example.com", map[string]string{
+		"example.org": "pointer",
+		"example.net": "alias",
+	})
+
+	expected := []DomainPointer{
+		{Source: "example.net", Target: "example.com", Type: DomainPointerAlias},
+		{Source: "example.org", Target: "example.com", Type: DomainPointerRedirect},
+	}
+
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("expected %+v, got %+v", expected, pointers)
+	}
+
+	if pointers = translateDomainPointers("example.com", nil); len(pointers) != 0 {
+		t.Errorf("expected no pointers, got %+v", pointers)
+	}
+}
+
+func TestDomainPointerFormValues(t *testing.T) {
+	tests := []struct {
+		expectedAlias string
+		pointer       DomainPointer
+	}{
+		{expectedAlias: "yes", pointer: DomainPointer{Source: "example.net", Target: "example.com", Type: DomainPointerAlias}},
+		{expectedAlias: "no", pointer: DomainPointer{Source: "example.org", Target: "example.com", Type: DomainPointerRedirect}},
+	}
+
+	for _, test := range tests {
+		body, err := test.pointer.formValues()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if body.Get("alias") != test.expectedAlias || body.Get("domain") != test.pointer.Target || body.Get("from") != test.pointer.Source {
+			t.Errorf("%v: unexpected form values %v", test.pointer.Type, body)
+		}
+	}
+
+	for _, pointer := range []DomainPointer{
+		{Source: "example.net", Target: "example.com"},
+		{Source: "example.net", Target: "example.com", Type: "pointer"},
+		{Target: "example.com", Type: DomainPointerAlias},
+	} {
+		if _, err := pointer.formValues(); err == nil {
+			t.Errorf("expected %+v to be rejected", pointer)
+		}
+	}
+}
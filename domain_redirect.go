@@ -0,0 +1,169 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+type URLRedirect struct {
+	Destination string `json:"destination" yaml:"destination"`
+	// Source is the path on the domain being redirected, e.g. "/old-page".
+	Source string `json:"source" yaml:"source"`
+	// StatusCode is either 301 (permanent) or 302 (temporary).
+	StatusCode int `json:"statusCode" yaml:"statusCode"`
+}
+
+// Validate checks the redirect locally.
+func (r *URLRedirect) Validate() error {
+	if !strings.HasPrefix(r.Source, "/") {
+		return fmt.Errorf("redirect source must start with /: %v", r.Source)
+	}
+
+	if r.StatusCode != http.StatusMovedPermanently && r.StatusCode != http.StatusFound {
+		return fmt.Errorf("invalid redirect status code: %d", r.StatusCode)
+	}
+
+	destination, err := url.Parse(r.Destination)
+	if err != nil || destination.Scheme == "" || destination.Host == "" {
+		return fmt.Errorf("redirect destination must be an absolute URL: %v", r.Destination)
+	}
+
+	return nil
+}
+
+// DeleteURLRedirects (user) deletes the redirects with the given source paths from the given domain.
+func (c *UserContext) DeleteURLRedirects(domain string, sources ...string) error {
+	var response apiGenericResponse
+
+	if len(sources) == 0 {
+		return errors.New("no redirect sources provided")
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+
+	for index, source := range sources {
+		body.Set("select"+cast.ToString(index), source)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_REDIRECT?action=delete", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Redirects Deleted" {
+		return fmt.Errorf("failed to delete url redirects: %v", response.Result)
+	}
+
+	return nil
+}
+
+// GetURLRedirects (user) returns the given domain's redirects, sorted by source path.
+func (c *UserContext) GetURLRedirects(domain string) ([]URLRedirect, error) {
+	// DA returns a map of source path to a query string containing the type and destination.
+	var rawRedirects map[string]string
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_REDIRECT?domain="+domain, nil, &rawRedirects); err != nil {
+		return nil, err
+	}
+
+	return parseURLRedirects(rawRedirects)
+}
+
+// SetURLRedirect (user) creates the given redirect, replacing any existing redirect with the same source path.
+//
+// DA has no way to edit a redirect, so replacing one isn't atomic: the existing redirect is deleted before the new one
+// is added, and if adding fails, the existing redirect is restored. The source path isn't redirected in between.
+func (c *UserContext) SetURLRedirect(domain string, redirect URLRedirect) error {
+	if err := redirect.Validate(); err != nil {
+		return err
+	}
+
+	redirects, err := c.GetURLRedirects(domain)
+	if err != nil {
+		return fmt.Errorf("failed to get url redirects: %w", err)
+	}
+
+	var previous *URLRedirect
+
+	for _, existing := range redirects {
+		if existing.Source != redirect.Source {
+			continue
+		}
+
+		if existing == redirect {
+			return nil
+		}
+
+		if err = c.DeleteURLRedirects(domain, redirect.Source); err != nil {
+			return err
+		}
+
+		previous = &existing
+
+		break
+	}
+
+	if err = c.createURLRedirect(domain, redirect); err != nil {
+		if previous != nil {
+			if restoreErr := c.createURLRedirect(domain, *previous); restoreErr != nil {
+				return fmt.Errorf("%w, and failed to restore the previous redirect: %w", err, restoreErr)
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// createURLRedirect (user) adds the given redirect, which must not already exist.
+func (c *UserContext) createURLRedirect(domain string, redirect URLRedirect) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("from", redirect.Source)
+	body.Set("to", redirect.Destination)
+	body.Set("type", strconv.Itoa(redirect.StatusCode))
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_REDIRECT?action=add", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Redirect Added" {
+		return fmt.Errorf("failed to set url redirect: %v", response.Result)
+	}
+
+	return nil
+}
+
+// parseURLRedirects converts DA's redirect map, e.g. {"/old": "type=301&to=https%3A%2F%2Fexample.com%2Fnew"}.
+func parseURLRedirects(rawRedirects map[string]string) ([]URLRedirect, error) {
+	redirects := make([]URLRedirect, 0, len(rawRedirects))
+
+	for source, rawRedirect := range rawRedirects {
+		values, err := url.ParseQuery(rawRedirect)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse redirect for %v: %w", source, err)
+		}
+
+		redirects = append(redirects, URLRedirect{
+			Destination: values.Get("to"),
+			Source:      source,
+			StatusCode:  cast.ToInt(values.Get("type")),
+		})
+	}
+
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].Source < redirects[j].Source
+	})
+
+	return redirects, nil
+}
@@ -0,0 +1,55 @@
+package directadmin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseURLRedirects(t *testing.T) {
+	redirects, err := parseURLRedirects(map[string]string{
+		"/shop": "type=302&to=https%3A%2F%2Fshop.example.com%2F",
+		"/old":  "type=301&to=https%3A%2F%2Fexample.com%2Fnew%3Fa%3D1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []URLRedirect{
+		{Destination: "https://example.com/new?a=1", Source: "/old", StatusCode: 301},
+		{Destination: "https://shop.example.com/", Source: "/shop", StatusCode: 302},
+	}
+
+	if !reflect.DeepEqual(redirects, expected) {
+		t.Errorf("expected %+v, got %+v", expected, redirects)
+	}
+
+	for _, redirect := range redirects {
+		if err = redirect.Validate(); err != nil {
+			t.Errorf("%v: %v", redirect.Source, err)
+		}
+	}
+
+	invalid := URLRedirect{Destination: "/relative", Source: "/old", StatusCode: 307}
+	if err = invalid.Validate(); err == nil {
+		t.Error("expected invalid redirect to fail validation")
+	}
+}
+
+func TestHotlinkProtectionTranslation(t *testing.T) {
+	protection := HotlinkProtection{
+		AllowBlankReferrer: true,
+		AllowedReferrers:   []string{"example.com", "cdn.example.net"},
+		Enabled:            true,
+		Extensions:         []string{".JPG", "png"},
+	}
+
+	rawProtection := protection.translate()
+	if rawProtection.Extensions != "jpg,png" {
+		t.Errorf("expected normalised extensions, got %q", rawProtection.Extensions)
+	}
+
+	protection.Extensions = []string{"jpg", "png"}
+	if translated := rawProtection.translate(); !reflect.DeepEqual(translated, protection) {
+		t.Errorf("expected %+v, got %+v", protection, translated)
+	}
+}
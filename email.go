@@ -5,20 +5,35 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/spf13/cast"
 )
 
-type EmailAccount struct {
-	DiskQuota int    `json:"diskQuota" yaml:"diskQuota"`
-	DiskUsage int    `json:"diskUsage" yaml:"diskUsage"`
-	Domain    string `json:"domain" yaml:"domain"`
-	Password  string `json:"password" yaml:"password"`
-	SendQuota int    `json:"sendQuota" yaml:"sendQuota"`
-	SendUsage int    `json:"sendUsage" yaml:"sendUsage"`
-	Suspended bool   `json:"suspended" yaml:"suspended"`
-	Username  string `json:"username" yaml:"username"`
-}
+type (
+	EmailAccount struct {
+		DiskQuota int `json:"diskQuota" yaml:"diskQuota"`
+		// DiskQuotaPercent is 0 when the account's disk quota is unlimited.
+		DiskQuotaPercent  float64        `json:"diskQuotaPercent" yaml:"diskQuotaPercent"`
+		DiskUsage         int            `json:"diskUsage" yaml:"diskUsage"`
+		Domain            string         `json:"domain" yaml:"domain"`
+		LastLogin         time.Time      `json:"lastLogin" yaml:"lastLogin"`
+		LastLoginProtocol string         `json:"lastLoginProtocol" yaml:"lastLoginProtocol"`
+		MessageCount      int            `json:"messageCount" yaml:"messageCount"`
+		Password          string         `json:"password" yaml:"password"`
+		SendQuota         int            `json:"sendQuota" yaml:"sendQuota"`
+		SendUsage         int            `json:"sendUsage" yaml:"sendUsage"`
+		SentPerDay        []EmailSentDay `json:"sentPerDay" yaml:"sentPerDay"`
+		Suspended         bool           `json:"suspended" yaml:"suspended"`
+		Username          string         `json:"username" yaml:"username"`
+	}
+
+	EmailSentDay struct {
+		Count int       `json:"count" yaml:"count"`
+		Day   time.Time `json:"day" yaml:"day"`
+	}
+)
 
 // CreateEmailAccount (user) creates the given email account.
 func (c *UserContext) CreateEmailAccount(emailAccount EmailAccount) error {
@@ -61,19 +76,32 @@ func (c *UserContext) DeleteEmailAccount(domain string, name string) error {
 	return nil
 }
 
+// GetEmailAccount (user) returns the single email account for the given address.
+func (c *UserContext) GetEmailAccount(address string) (*EmailAccount, error) {
+	username, domain, ok := strings.Cut(address, "@")
+	if !ok || username == "" || domain == "" {
+		return nil, fmt.Errorf("invalid email address: %v", address)
+	}
+
+	emailAccounts, err := c.GetEmailAccounts(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, emailAccount := range emailAccounts {
+		if strings.EqualFold(emailAccount.Username, username) {
+			return &emailAccount, nil
+		}
+	}
+
+	return nil, fmt.Errorf("email account not found: %v", address)
+}
+
 // GetEmailAccounts (user) returns an array of email accounts belonging to the provided domain.
 func (c *UserContext) GetEmailAccounts(domain string) ([]EmailAccount, error) {
 	var emailAccounts []EmailAccount
 	rawEmailAccounts := struct {
-		EmailAccounts map[string]struct {
-			Sent      any    `json:"sent"`
-			Suspended string `json:"suspended"`
-			Usage     struct {
-				DiskQuota string `json:"quota"`
-				DiskUsage string `json:"usage"`
-			} `json:"usage"`
-			Username string `json:"account"`
-		} `json:"emails"`
+		EmailAccounts map[string]rawEmailAccount `json:"emails"`
 	}{}
 
 	if _, err := c.makeRequestOld(http.MethodGet, "EMAIL_POP?bytes=yes&domain="+domain, nil, &rawEmailAccounts); err != nil {
@@ -82,22 +110,7 @@ func (c *UserContext) GetEmailAccounts(domain string) ([]EmailAccount, error) {
 
 	for id, emailAccount := range rawEmailAccounts.EmailAccounts {
 		if id != "info" {
-			email := EmailAccount{
-				DiskQuota: cast.ToInt(emailAccount.Usage.DiskQuota),
-				DiskUsage: cast.ToInt(emailAccount.Usage.DiskUsage),
-				Domain:    domain,
-				Suspended: parseOnOff(emailAccount.Suspended),
-				Username:  emailAccount.Username,
-			}
-
-			// This is necessary because DA either returns a string, or a map with the disk usage in a "usage" field
-			// depending on which usage endpoint we hit.
-			if emailAccountSent, ok := emailAccount.Sent.(map[string]any); ok {
-				email.SendQuota = cast.ToInt(emailAccountSent["send_limit"])
-				email.SendUsage = cast.ToInt(emailAccountSent["sent"])
-			}
-
-			emailAccounts = append(emailAccounts, email)
+			emailAccounts = append(emailAccounts, emailAccount.translate(domain))
 		}
 	}
 
@@ -0,0 +1,506 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	EmailAuthCheckDKIM  = EmailAuthCheck("dkim")
+	EmailAuthCheckDMARC = EmailAuthCheck("dmarc")
+	EmailAuthCheckSPF   = EmailAuthCheck("spf")
+
+	EmailAuthSeverityError   = EmailAuthSeverity("error")
+	EmailAuthSeverityWarning = EmailAuthSeverity("warning")
+)
+
+type (
+	EmailAuthCheck    string
+	EmailAuthSeverity string
+
+	DKIMKey struct {
+		Domain   string `json:"domain"`
+		Selector string `json:"selector"`
+		// Name is the record's full hostname, e.g. "x._domainkey.example.com".
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	DKIMRecord struct {
+		KeyType   string `json:"keyType"`
+		PublicKey string `json:"publicKey"`
+		Version   string `json:"version"`
+	}
+
+	DMARCRecord struct {
+		Percent         int      `json:"percent"`
+		Policy          string   `json:"policy"`
+		ReportAggregate []string `json:"reportAggregate"`
+		ReportForensic  []string `json:"reportForensic"`
+		SubdomainPolicy string   `json:"subdomainPolicy"`
+		Version         string   `json:"version"`
+	}
+
+	EmailAuthProblem struct {
+		Check    EmailAuthCheck    `json:"check"`
+		Message  string            `json:"message"`
+		Severity EmailAuthSeverity `json:"severity"`
+	}
+
+	EmailAuthStatus struct {
+		DKIM      *DKIMRecord        `json:"dkim"`
+		DMARC     *DMARCRecord       `json:"dmarc"`
+		Domain    string             `json:"domain"`
+		Problems  []EmailAuthProblem `json:"problems"`
+		ServerIPs []string           `json:"serverIPs"`
+		SPF       *SPFRecord         `json:"spf"`
+	}
+
+	SPFMechanism struct {
+		// Qualifier is one of "+", "-", "~" or "?".
+		Qualifier string `json:"qualifier"`
+		Type      string `json:"type"`
+		Value     string `json:"value"`
+	}
+
+	SPFRecord struct {
+		Mechanisms []SPFMechanism    `json:"mechanisms"`
+		Modifiers  map[string]string `json:"modifiers"`
+	}
+)
+
+// OK returns whether no errors were found. Warnings are ignored.
+func (s *EmailAuthStatus) OK() bool {
+	for _, problem := range s.Problems {
+		if problem.Severity == EmailAuthSeverityError {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *EmailAuthStatus) addProblem(check EmailAuthCheck, severity EmailAuthSeverity, format string, args ...any) {
+	s.Problems = append(s.Problems, EmailAuthProblem{
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: severity,
+	})
+}
+
+// All returns the qualifier of the record's "all" mechanism, or an empty string if it has none.
+func (s *SPFRecord) All() string {
+	for _, mechanism := range s.Mechanisms {
+		if mechanism.Type == "all" {
+			return mechanism.Qualifier
+		}
+	}
+
+	return ""
+}
+
+// hasExternalLookups reports whether the record delegates to another domain's SPF record through an include mechanism
+// or a redirect modifier.
+func (s *SPFRecord) hasExternalLookups() bool {
+	if _, ok := s.Modifiers["redirect"]; ok {
+		return true
+	}
+
+	for _, mechanism := range s.Mechanisms {
+		if mechanism.Type == "include" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetDKIMKey (user) returns the DKIM selector and TXT record value DA generated for the given domain, so it can be
+// published with an external DNS provider. DKIM must be enabled for the domain via ToggleDKIM first.
+func (c *UserContext) GetDKIMKey(domain string) (*DKIMKey, error) {
+	dnsRecords, err := c.GetDNSRecords(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dns records: %w", err)
+	}
+
+	if key := findDKIMKey(domain, dnsRecords); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("no DKIM key found for %v, ensure DKIM is enabled", domain)
+}
+
+// EmailAuthReport (user) checks the given domain's SPF, DKIM and DMARC records against its DNS zone and the IPs it's
+// hosted on.
+func (c *UserContext) EmailAuthReport(domain string) (*EmailAuthStatus, error) {
+	dnsRecords, err := c.GetDNSRecords(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dns records: %w", err)
+	}
+
+	domainData, err := c.GetDomain(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain: %w", err)
+	}
+
+	serverIPs := domainData.IPAddresses
+	if len(serverIPs) == 0 && c.User.Config.IP != "" {
+		serverIPs = []string{c.User.Config.IP}
+	}
+
+	return checkEmailAuth(domain, dnsRecords, serverIPs), nil
+}
+
+// checkEmailAuth builds an EmailAuthStatus from the given zone without contacting DA.
+func checkEmailAuth(domain string, dnsRecords []DNSRecord, serverIPs []string) *EmailAuthStatus {
+	status := &EmailAuthStatus{
+		Domain:    domain,
+		Problems:  []EmailAuthProblem{},
+		ServerIPs: serverIPs,
+	}
+
+	// SPF.
+	var spfValues []string
+	for _, value := range findTXTValues(domain, "@", dnsRecords) {
+		if strings.HasPrefix(strings.ToLower(value), "v=spf1") {
+			spfValues = append(spfValues, value)
+		}
+	}
+
+	switch {
+	case len(spfValues) == 0:
+		status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityError, "no SPF record found")
+	case len(spfValues) > 1:
+		status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityError, "%d SPF records found, only one is allowed", len(spfValues))
+	default:
+		spf, err := parseSPF(spfValues[0])
+		if err != nil {
+			status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityError, "invalid SPF record: %v", err)
+			break
+		}
+
+		status.SPF = spf
+
+		switch spf.All() {
+		case "":
+			if _, ok := spf.Modifiers["redirect"]; !ok {
+				status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityWarning, "SPF record has no \"all\" mechanism")
+			}
+		case "+":
+			status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityError, "SPF record allows any server to send with \"+all\"")
+		case "?":
+			status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityWarning, "SPF record uses a neutral \"?all\"")
+		}
+
+		for _, ip := range serverIPs {
+			switch {
+			case spfAuthorizesIP(spf, domain, ip, dnsRecords):
+			case spf.hasExternalLookups():
+				// The IP may well be covered by an include or redirect, we just can't tell offline.
+				status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityWarning, "SPF authorization of server IP %v could not be verified locally", ip)
+			default:
+				status.addProblem(EmailAuthCheckSPF, EmailAuthSeverityError, "SPF record does not authorize server IP %v", ip)
+			}
+		}
+	}
+
+	// DKIM.
+	if key := findDKIMKey(domain, dnsRecords); key == nil {
+		status.addProblem(EmailAuthCheckDKIM, EmailAuthSeverityError, "no DKIM record found")
+	} else if dkim, err := parseDKIM(key.Value); err != nil {
+		status.addProblem(EmailAuthCheckDKIM, EmailAuthSeverityError, "invalid DKIM record for selector %v: %v", key.Selector, err)
+	} else {
+		status.DKIM = dkim
+	}
+
+	// DMARC.
+	var dmarcValues []string
+	for _, value := range findTXTValues(domain, "_dmarc", dnsRecords) {
+		if strings.HasPrefix(strings.ToLower(value), "v=dmarc1") {
+			dmarcValues = append(dmarcValues, value)
+		}
+	}
+
+	switch {
+	case len(dmarcValues) == 0:
+		status.addProblem(EmailAuthCheckDMARC, EmailAuthSeverityWarning, "no DMARC record found")
+	case len(dmarcValues) > 1:
+		status.addProblem(EmailAuthCheckDMARC, EmailAuthSeverityError, "%d DMARC records found, only one is allowed", len(dmarcValues))
+	default:
+		dmarc, err := parseDMARC(dmarcValues[0])
+		if err != nil {
+			status.addProblem(EmailAuthCheckDMARC, EmailAuthSeverityError, "invalid DMARC record: %v", err)
+			break
+		}
+
+		status.DMARC = dmarc
+
+		if dmarc.Policy == "none" {
+			status.addProblem(EmailAuthCheckDMARC, EmailAuthSeverityWarning, "DMARC policy is \"none\", failing mail is only reported")
+		}
+
+		if len(dmarc.ReportAggregate) == 0 {
+			status.addProblem(EmailAuthCheckDMARC, EmailAuthSeverityWarning, "DMARC record has no aggregate report address (rua)")
+		}
+	}
+
+	return status
+}
+
+// findDKIMKey returns the first DKIM key published under "<selector>._domainkey" in the given zone.
+func findDKIMKey(domain string, dnsRecords []DNSRecord) *DKIMKey {
+	for _, dnsRecord := range dnsRecords {
+		if !strings.EqualFold(dnsRecord.Type, "TXT") {
+			continue
+		}
+
+		name := relativeDNSName(dnsRecord.Name, domain)
+
+		selector, ok := strings.CutSuffix(name, "._domainkey")
+		if !ok || selector == "" {
+			continue
+		}
+
+		if !strings.Contains(dnsRecord.Value, "p=") {
+			continue
+		}
+
+		return &DKIMKey{
+			Domain:   domain,
+			Name:     selector + "._domainkey." + domain,
+			Selector: selector,
+			Value:    dnsRecord.Value,
+		}
+	}
+
+	return nil
+}
+
+// findTXTValues returns the values of all TXT records with the given relative name.
+func findTXTValues(domain string, name string, dnsRecords []DNSRecord) []string {
+	var values []string
+
+	for _, dnsRecord := range dnsRecords {
+		if strings.EqualFold(dnsRecord.Type, "TXT") && relativeDNSName(dnsRecord.Name, domain) == name {
+			values = append(values, dnsRecord.Value)
+		}
+	}
+
+	return values
+}
+
+// parseDKIM parses a DKIM TXT record value, e.g. "v=DKIM1; k=rsa; p=MIGf...".
+func parseDKIM(value string) (*DKIMRecord, error) {
+	tags, err := parseTagList(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dkim := &DKIMRecord{
+		KeyType:   tags["k"],
+		PublicKey: tags["p"],
+		Version:   tags["v"],
+	}
+
+	if dkim.Version != "" && dkim.Version != "DKIM1" {
+		return nil, fmt.Errorf("unsupported version: %v", dkim.Version)
+	}
+
+	if dkim.KeyType == "" {
+		dkim.KeyType = "rsa"
+	}
+
+	if dkim.PublicKey == "" {
+		return nil, errors.New("no public key found (empty p= tag means the key was revoked)")
+	}
+
+	return dkim, nil
+}
+
+// parseDMARC parses a DMARC TXT record value, e.g. "v=DMARC1; p=quarantine; rua=mailto:dmarc@example.com".
+func parseDMARC(value string) (*DMARCRecord, error) {
+	tags, err := parseTagList(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if tags["v"] != "DMARC1" {
+		return nil, fmt.Errorf("unsupported version: %v", tags["v"])
+	}
+
+	dmarc := &DMARCRecord{
+		Percent:         100,
+		Policy:          strings.ToLower(tags["p"]),
+		ReportAggregate: splitDMARCURIs(tags["rua"]),
+		ReportForensic:  splitDMARCURIs(tags["ruf"]),
+		SubdomainPolicy: strings.ToLower(tags["sp"]),
+		Version:         tags["v"],
+	}
+
+	switch dmarc.Policy {
+	case "none", "quarantine", "reject":
+	case "":
+		return nil, errors.New("no policy (p=) found")
+	default:
+		return nil, fmt.Errorf("invalid policy: %v", dmarc.Policy)
+	}
+
+	if rawPercent, ok := tags["pct"]; ok {
+		if _, err = fmt.Sscanf(rawPercent, "%d", &dmarc.Percent); err != nil || dmarc.Percent < 0 || dmarc.Percent > 100 {
+			return nil, fmt.Errorf("invalid percentage: %v", rawPercent)
+		}
+	}
+
+	return dmarc, nil
+}
+
+// parseSPF parses an SPF TXT record value, e.g. "v=spf1 a mx ip4:192.0.2.1 ~all".
+func parseSPF(value string) (*SPFRecord, error) {
+	terms := strings.Fields(value)
+	if len(terms) == 0 || !strings.EqualFold(terms[0], "v=spf1") {
+		return nil, errors.New("record doesn't start with v=spf1")
+	}
+
+	spf := &SPFRecord{
+		Mechanisms: []SPFMechanism{},
+		Modifiers:  make(map[string]string),
+	}
+
+	for _, term := range terms[1:] {
+		// Modifiers are name=value, mechanisms use a colon or slash for their value.
+		if name, modifierValue, ok := strings.Cut(term, "="); ok && !strings.ContainsAny(name, ":/") {
+			spf.Modifiers[strings.ToLower(name)] = modifierValue
+			continue
+		}
+
+		mechanism := SPFMechanism{Qualifier: "+"}
+
+		if strings.ContainsAny(term[:1], "+-~?") {
+			mechanism.Qualifier = term[:1]
+			term = term[1:]
+		}
+
+		mechanismType, mechanismValue, _ := strings.Cut(term, ":")
+		if slashIndex := strings.Index(mechanismType, "/"); slashIndex != -1 {
+			mechanismValue = mechanismType[slashIndex:]
+			mechanismType = mechanismType[:slashIndex]
+		}
+
+		mechanism.Type = strings.ToLower(mechanismType)
+		mechanism.Value = mechanismValue
+
+		switch mechanism.Type {
+		case "a", "all", "exists", "include", "ip4", "ip6", "mx", "ptr":
+		default:
+			return nil, fmt.Errorf("unknown mechanism: %v", term)
+		}
+
+		spf.Mechanisms = append(spf.Mechanisms, mechanism)
+	}
+
+	return spf, nil
+}
+
+// parseTagList parses a semicolon-separated DKIM/DMARC tag list.
+func parseTagList(value string) (map[string]string, error) {
+	tags := make(map[string]string)
+
+	for _, tag := range strings.Split(value, ";") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		name, tagValue, ok := strings.Cut(tag, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tag: %v", tag)
+		}
+
+		// Public keys may be split with whitespace when published.
+		tags[strings.TrimSpace(name)] = strings.Join(strings.Fields(tagValue), "")
+	}
+
+	return tags, nil
+}
+
+// spfAuthorizesIP checks whether the given SPF record passes the IP using only the local zone. Includes and other
+// lookups outside the zone can't be resolved offline, so they're not followed.
+func spfAuthorizesIP(spf *SPFRecord, domain string, ip string, dnsRecords []DNSRecord) bool {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return false
+	}
+
+	for _, mechanism := range spf.Mechanisms {
+		if mechanism.Qualifier != "+" {
+			continue
+		}
+
+		switch mechanism.Type {
+		case "a":
+			if zoneHostHasIP(domain, spfTargetName(mechanism.Value, domain), parsedIP, dnsRecords) {
+				return true
+			}
+		case "ip4", "ip6":
+			if _, network, err := net.ParseCIDR(mechanism.Value); err == nil {
+				if network.Contains(parsedIP) {
+					return true
+				}
+			} else if mechanismIP := net.ParseIP(mechanism.Value); mechanismIP != nil && mechanismIP.Equal(parsedIP) {
+				return true
+			}
+		case "mx":
+			target := spfTargetName(mechanism.Value, domain)
+
+			for _, dnsRecord := range dnsRecords {
+				if !strings.EqualFold(dnsRecord.Type, "MX") || relativeDNSName(dnsRecord.Name, domain) != target {
+					continue
+				}
+
+				if zoneHostHasIP(domain, relativeDNSName(dnsRecord.Value, domain), parsedIP, dnsRecords) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// spfTargetName returns the relative zone name an a/mx mechanism refers to, ignoring any CIDR suffix, so "a/24" refers
+// to the apex.
+func spfTargetName(value string, domain string) string {
+	value, _, _ = strings.Cut(value, "/")
+	if value == "" {
+		return "@"
+	}
+
+	return relativeDNSName(value+".", domain)
+}
+
+// zoneHostHasIP checks whether the given relative name has an A/AAAA record for the IP in the local zone.
+func zoneHostHasIP(domain string, name string, ip net.IP, dnsRecords []DNSRecord) bool {
+	for _, dnsRecord := range dnsRecords {
+		if (strings.EqualFold(dnsRecord.Type, "A") || strings.EqualFold(dnsRecord.Type, "AAAA")) && relativeDNSName(dnsRecord.Name, domain) == name {
+			if recordIP := net.ParseIP(dnsRecord.Value); recordIP != nil && recordIP.Equal(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func splitDMARCURIs(value string) []string {
+	var uris []string
+
+	for _, uri := range strings.Split(value, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+
+	return uris
+}
@@ -0,0 +1,87 @@
+package directadmin
+
+import "testing"
+
+func TestParseSPF(t *testing.T) {
+	spf, err := parseSPF("v=spf1 a mx ip4:192.0.2.0/24 -ip6:2001:db8::1 include:_spf.example.net redirect=_spf.example.com ~all")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spf.Mechanisms) != 6 || spf.All() != "~" || spf.Modifiers["redirect"] != "_spf.example.com" {
+		t.Fatalf("unexpected SPF record: %+v", spf)
+	}
+
+	if mechanism := spf.Mechanisms[3]; mechanism.Qualifier != "-" || mechanism.Type != "ip6" || mechanism.Value != "2001:db8::1" {
+		t.Fatalf("unexpected ip6 mechanism: %+v", mechanism)
+	}
+
+	if _, err = parseSPF("v=spf1 bogus:thing -all"); err == nil {
+		t.Fatal("expected error for unknown mechanism")
+	}
+}
+
+func TestParseDMARC(t *testing.T) {
+	dmarc, err := parseDMARC("v=DMARC1; p=quarantine; pct=50; rua=mailto:a@example.com,mailto:b@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dmarc.Policy != "quarantine" || dmarc.Percent != 50 || len(dmarc.ReportAggregate) != 2 {
+		t.Fatalf("unexpected DMARC record: %+v", dmarc)
+	}
+
+	if _, err = parseDMARC("v=DMARC1; p=maybe"); err == nil {
+		t.Fatal("expected error for invalid policy")
+	}
+}
+
+func TestSPFTargetName(t *testing.T) {
+	spf, err := parseSPF("v=spf1 a/24 mx/24 a:mail.example.com/28 mx:example.net -all")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"@", "@", "mail", "example.net."}
+	for i, name := range expected {
+		if target := spfTargetName(spf.Mechanisms[i].Value, "example.com"); target != name {
+			t.Errorf("%v%v: expected %q, got %q", spf.Mechanisms[i].Type, spf.Mechanisms[i].Value, name, target)
+		}
+	}
+
+	if target := spfTargetName("", "example.com"); target != "@" {
+		t.Errorf("expected a bare mechanism to target the apex, got %q", target)
+	}
+}
+
+func TestCheckEmailAuth(t *testing.T) {
+	dnsRecords := []DNSRecord{
+		{Name: "example.com.", Type: "A", Value: "192.0.2.10"},
+		{Name: "mail", Type: "A", Value: "192.0.2.20"},
+		{Name: "example.com.", Priority: 10, Type: "MX", Value: "mail.example.com."},
+		{Name: "example.com.", Type: "TXT", Value: "v=spf1 a mx ~all"},
+		{Name: "x._domainkey", Type: "TXT", Value: "v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC"},
+		{Name: "_dmarc", Type: "TXT", Value: "v=DMARC1; p=reject; rua=mailto:dmarc@example.com"},
+	}
+
+	status := checkEmailAuth("example.com", dnsRecords, []string{"192.0.2.10", "192.0.2.20"})
+	if !status.OK() || len(status.Problems) != 0 {
+		t.Fatalf("expected no problems, got %+v", status.Problems)
+	}
+
+	if status.DKIM == nil || status.DKIM.PublicKey != "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC" {
+		t.Fatalf("unexpected DKIM record: %+v", status.DKIM)
+	}
+
+	status = checkEmailAuth("example.com", dnsRecords[:4], []string{"192.0.2.30"})
+	if status.OK() || len(status.Problems) != 3 {
+		t.Fatalf("expected SPF and DKIM errors plus a DMARC warning, got %+v", status.Problems)
+	}
+
+	// An include may authorize the IP, so it's only a warning.
+	dnsRecords[3].Value = "v=spf1 a mx include:_spf.example.net ~all"
+	status = checkEmailAuth("example.com", dnsRecords, []string{"192.0.2.30"})
+	if !status.OK() || len(status.Problems) != 1 || status.Problems[0].Severity != EmailAuthSeverityWarning {
+		t.Fatalf("expected an SPF warning, got %+v", status.Problems)
+	}
+}
@@ -0,0 +1,283 @@
+package directadmin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	EmailFilterActionDrop     = EmailFilterAction("drop")
+	EmailFilterActionMove     = EmailFilterAction("move")
+	EmailFilterActionRedirect = EmailFilterAction("redirect")
+
+	EmailFilterFieldBody    = EmailFilterField("body")
+	EmailFilterFieldSender  = EmailFilterField("sender")
+	EmailFilterFieldSize    = EmailFilterField("size")
+	EmailFilterFieldSubject = EmailFilterField("subject")
+
+	EmailFilterOperatorContains    = EmailFilterOperator("contains")
+	EmailFilterOperatorEndsWith    = EmailFilterOperator("endsWith")
+	EmailFilterOperatorEquals      = EmailFilterOperator("equals")
+	EmailFilterOperatorGreaterThan = EmailFilterOperator("greaterThan")
+	EmailFilterOperatorLessThan    = EmailFilterOperator("lessThan")
+	EmailFilterOperatorStartsWith  = EmailFilterOperator("startsWith")
+)
+
+type (
+	EmailFilterAction   string
+	EmailFilterField    string
+	EmailFilterOperator string
+
+	// EmailFilter is a single message filter. Filters with an empty Mailbox apply to the whole domain (Exim), otherwise
+	// they only apply to the given mailbox (Sieve).
+	EmailFilter struct {
+		Action EmailFilterAction `json:"action" yaml:"action"`
+		// Destination is the address to redirect to, or the folder to move to. It is ignored for drop actions.
+		Destination string              `json:"destination,omitempty" yaml:"destination,omitempty"`
+		Domain      string              `json:"domain" yaml:"domain"`
+		Field       EmailFilterField    `json:"field" yaml:"field"`
+		ID          string              `json:"id,omitempty" yaml:"-"`
+		Mailbox     string              `json:"mailbox,omitempty" yaml:"mailbox,omitempty"`
+		Operator    EmailFilterOperator `json:"operator" yaml:"operator"`
+		Value       string              `json:"value" yaml:"value"`
+	}
+
+	// EmailFilterSet is the portable form of a domain's or mailbox's filters, as produced by ExportEmailFilters.
+	EmailFilterSet struct {
+		Domain  string         `json:"domain" yaml:"domain"`
+		Filters []*EmailFilter `json:"filters" yaml:"filters"`
+		Mailbox string         `json:"mailbox,omitempty" yaml:"mailbox,omitempty"`
+	}
+
+	rawEmailFilter struct {
+		Action      string `json:"action"`
+		Destination string `json:"destination"`
+		Operator    string `json:"operator"`
+		Type        string `json:"type"`
+		Value       string `json:"value"`
+	}
+)
+
+// Validate checks the filter locally, without contacting DA.
+func (f *EmailFilter) Validate() error {
+	if f.Domain == "" {
+		return errors.New("no domain provided")
+	}
+
+	if f.Value == "" {
+		return errors.New("no value provided")
+	}
+
+	switch f.Field {
+	case EmailFilterFieldBody, EmailFilterFieldSender, EmailFilterFieldSubject:
+		switch f.Operator {
+		case EmailFilterOperatorContains, EmailFilterOperatorEndsWith, EmailFilterOperatorEquals, EmailFilterOperatorStartsWith:
+		default:
+			return fmt.Errorf("operator %q is not supported for field %q", f.Operator, f.Field)
+		}
+	case EmailFilterFieldSize:
+		switch f.Operator {
+		case EmailFilterOperatorGreaterThan, EmailFilterOperatorLessThan:
+		default:
+			return fmt.Errorf("operator %q is not supported for field %q", f.Operator, f.Field)
+		}
+
+		if size, err := cast.ToIntE(f.Value); err != nil || size <= 0 {
+			return fmt.Errorf("invalid size value: %v", f.Value)
+		}
+	default:
+		return fmt.Errorf("invalid filter field: %q", f.Field)
+	}
+
+	switch f.Action {
+	case EmailFilterActionDrop:
+	case EmailFilterActionMove:
+		if f.Mailbox == "" {
+			return errors.New("move actions are only supported on mailbox filters")
+		}
+
+		if f.Destination == "" {
+			return errors.New("no destination folder provided")
+		}
+	case EmailFilterActionRedirect:
+		if _, err := mail.ParseAddress(f.Destination); err != nil {
+			return fmt.Errorf("invalid redirect address %q: %w", f.Destination, err)
+		}
+	default:
+		return fmt.Errorf("invalid filter action: %q", f.Action)
+	}
+
+	return nil
+}
+
+// CreateEmailFilter (user) validates and appends the given filter to the end of the domain's or mailbox's filters.
+func (c *UserContext) CreateEmailFilter(filter EmailFilter) error {
+	var response apiGenericResponse
+
+	if err := filter.Validate(); err != nil {
+		return fmt.Errorf("invalid email filter: %w", err)
+	}
+
+	body := url.Values{}
+	body.Set("action", string(filter.Action))
+	body.Set("destination", filter.Destination)
+	body.Set("domain", filter.Domain)
+	body.Set("operator", string(filter.Operator))
+	body.Set("type", string(filter.Field))
+	body.Set("value", filter.Value)
+
+	if filter.Mailbox != "" {
+		body.Set("user", filter.Mailbox)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_EMAIL_FILTER?action=add", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Filter Added" {
+		return fmt.Errorf("failed to create email filter: %v", response.Result)
+	}
+
+	return nil
+}
+
+// DeleteEmailFilters (user) deletes the filters with the given IDs. Leave mailbox empty for domain-level filters.
+func (c *UserContext) DeleteEmailFilters(domain string, mailbox string, ids ...string) error {
+	var response apiGenericResponse
+
+	if len(ids) == 0 {
+		return errors.New("no filter IDs provided")
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+
+	if mailbox != "" {
+		body.Set("user", mailbox)
+	}
+
+	for index, id := range ids {
+		body.Set("select"+cast.ToString(index), id)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_EMAIL_FILTER?action=delete", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Filters Deleted" {
+		return fmt.Errorf("failed to delete email filters: %v", response.Result)
+	}
+
+	return nil
+}
+
+// GetEmailFilters (user) returns the filters for the given domain, in the order DA applies them. Leave mailbox empty
+// for domain-level filters.
+func (c *UserContext) GetEmailFilters(domain string, mailbox string) ([]*EmailFilter, error) {
+	var rawFilters map[string]rawEmailFilter
+
+	query := url.Values{}
+	query.Set("domain", domain)
+
+	if mailbox != "" {
+		query.Set("user", mailbox)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_EMAIL_FILTER?"+query.Encode(), nil, &rawFilters); err != nil {
+		return nil, err
+	}
+
+	// DA keys filters by their position, so sort numerically to keep the order they're applied in.
+	ids := make([]string, 0, len(rawFilters))
+	for id := range rawFilters {
+		if _, err := cast.ToIntE(id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return cast.ToInt(ids[i]) < cast.ToInt(ids[j])
+	})
+
+	filters := make([]*EmailFilter, 0, len(ids))
+
+	for _, id := range ids {
+		rawFilter := rawFilters[id]
+
+		filters = append(filters, &EmailFilter{
+			Action:      EmailFilterAction(rawFilter.Action),
+			Destination: rawFilter.Destination,
+			Domain:      domain,
+			Field:       EmailFilterField(rawFilter.Type),
+			ID:          id,
+			Mailbox:     mailbox,
+			Operator:    EmailFilterOperator(rawFilter.Operator),
+			Value:       rawFilter.Value,
+		})
+	}
+
+	return filters, nil
+}
+
+// ReorderEmailFilters (user) sets the order DA applies filters in. Every existing filter ID must be provided, in the
+// desired order. Leave mailbox empty for domain-level filters.
+func (c *UserContext) ReorderEmailFilters(domain string, mailbox string, ids ...string) error {
+	var response apiGenericResponse
+
+	if len(ids) == 0 {
+		return errors.New("no filter IDs provided")
+	}
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("order", strings.Join(ids, ","))
+
+	if mailbox != "" {
+		body.Set("user", mailbox)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_EMAIL_FILTER?action=reorder", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Filters Reordered" {
+		return fmt.Errorf("failed to reorder email filters: %v", response.Result)
+	}
+
+	return nil
+}
+
+// ExportEmailFilters returns the given filters in their portable JSON form. EmailFilterSet carries yaml tags matching
+// the rest of the package, so it can be marshalled to YAML by the caller instead.
+func ExportEmailFilters(domain string, mailbox string, filters []*EmailFilter) ([]byte, error) {
+	filterSet := EmailFilterSet{
+		Domain:  domain,
+		Filters: make([]*EmailFilter, 0, len(filters)),
+		Mailbox: mailbox,
+	}
+
+	for _, filter := range filters {
+		if err := filter.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid email filter %v: %w", filter.ID, err)
+		}
+
+		exported := *filter
+		exported.ID = ""
+		filterSet.Filters = append(filterSet.Filters, &exported)
+	}
+
+	data, err := json.MarshalIndent(filterSet, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing email filters: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,24 @@
+package directadmin
+
+import "testing"
+
+func TestEmailFilterValidate(t *testing.T) {
+	tests := []struct {
+		filter EmailFilter
+		valid  bool
+	}{
+		{EmailFilter{Action: EmailFilterActionDrop, Domain: "example.com", Field: EmailFilterFieldSender, Operator: EmailFilterOperatorEndsWith, Value: "@spam.tld"}, true},
+		{EmailFilter{Action: EmailFilterActionRedirect, Destination: "abuse@example.com", Domain: "example.com", Field: EmailFilterFieldSubject, Operator: EmailFilterOperatorContains, Value: "invoice"}, true},
+		{EmailFilter{Action: EmailFilterActionMove, Destination: "Junk", Domain: "example.com", Field: EmailFilterFieldSize, Mailbox: "info", Operator: EmailFilterOperatorGreaterThan, Value: "1048576"}, true},
+		{EmailFilter{Action: EmailFilterActionMove, Destination: "Junk", Domain: "example.com", Field: EmailFilterFieldBody, Operator: EmailFilterOperatorContains, Value: "casino"}, false},
+		{EmailFilter{Action: EmailFilterActionDrop, Domain: "example.com", Field: EmailFilterFieldSize, Operator: EmailFilterOperatorContains, Value: "100"}, false},
+		{EmailFilter{Action: EmailFilterActionDrop, Domain: "example.com", Field: EmailFilterFieldSize, Operator: EmailFilterOperatorLessThan, Value: "big"}, false},
+		{EmailFilter{Action: EmailFilterActionRedirect, Destination: "not-an-address", Domain: "example.com", Field: EmailFilterFieldSender, Operator: EmailFilterOperatorEquals, Value: "a@b.tld"}, false},
+	}
+
+	for i, test := range tests {
+		if err := test.filter.Validate(); (err == nil) != test.valid {
+			t.Errorf("test %d: expected valid=%v, got error %v", i, test.valid, err)
+		}
+	}
+}
@@ -1,32 +1,161 @@
 package directadmin
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/mail"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cast"
 )
 
+const (
+	EmailForwarderDestinationAddress = EmailForwarderDestinationType("address")
+	EmailForwarderDestinationFile    = EmailForwarderDestinationType("file")
+	EmailForwarderDestinationPipe    = EmailForwarderDestinationType("pipe")
+	EmailForwarderDestinationSpecial = EmailForwarderDestinationType("special")
+
+	EmailForwarderBlackhole = ":blackhole:"
+	EmailForwarderFail      = ":fail:"
+)
+
+type (
+	EmailForwarderDestinationType string
+
+	EmailForwarder struct {
+		Destinations []EmailForwarderDestination `json:"destinations" yaml:"destinations"`
+		Domain       string                      `json:"domain" yaml:"domain"`
+		Name         string                      `json:"name" yaml:"name"`
+	}
+
+	EmailForwarderDestination struct {
+		Type EmailForwarderDestinationType `json:"type" yaml:"type"`
+		// Value is the address, the pipe's command, the file path, or the special keyword (optionally followed by a
+		// message for :fail:).
+		Value string `json:"value" yaml:"value"`
+	}
+
+	// EmailForwarderChanges lists what SyncEmailForwarders did (or needed to do) to reach the desired state.
+	EmailForwarderChanges struct {
+		Create []*EmailForwarder `json:"create"`
+		Delete []string          `json:"delete"`
+		Update []*EmailForwarder `json:"update"`
+	}
+)
+
+// ParseEmailForwarderDestination converts DA's raw destination string into its typed form.
+func ParseEmailForwarderDestination(rawDestination string) EmailForwarderDestination {
+	rawDestination = strings.TrimSpace(rawDestination)
+
+	switch {
+	case strings.HasPrefix(rawDestination, "|"):
+		return EmailForwarderDestination{Type: EmailForwarderDestinationPipe, Value: strings.TrimSpace(rawDestination[1:])}
+	case strings.HasPrefix(rawDestination, "/"):
+		return EmailForwarderDestination{Type: EmailForwarderDestinationFile, Value: rawDestination}
+	case strings.HasPrefix(rawDestination, EmailForwarderBlackhole), strings.HasPrefix(rawDestination, EmailForwarderFail):
+		return EmailForwarderDestination{Type: EmailForwarderDestinationSpecial, Value: rawDestination}
+	}
+
+	return EmailForwarderDestination{Type: EmailForwarderDestinationAddress, Value: rawDestination}
+}
+
+// String returns the destination in the raw form DA expects.
+func (d EmailForwarderDestination) String() string {
+	if d.Type == EmailForwarderDestinationPipe {
+		return "|" + d.Value
+	}
+
+	return d.Value
+}
+
+// Validate checks the forwarder locally. Pipe destinations are only accepted if allowPipe is true, which should
+// reflect Session.DirectadminConfig.AllowForwarderPipe.
+func (f *EmailForwarder) Validate(allowPipe bool) error {
+	if f.Name == "" {
+		return errors.New("no forwarder name provided")
+	}
+
+	if len(f.Destinations) == 0 {
+		return fmt.Errorf("no destinations provided for forwarder %v", f.Name)
+	}
+
+	for _, destination := range f.Destinations {
+		switch destination.Type {
+		case EmailForwarderDestinationAddress:
+			if strings.ContainsAny(destination.Value, ", \t") || destination.Value == "" {
+				return fmt.Errorf("invalid address destination: %q", destination.Value)
+			}
+
+			// Bare local parts are allowed, DA delivers them to the forwarder's own domain.
+			if strings.Contains(destination.Value, "@") {
+				if _, err := mail.ParseAddress(destination.Value); err != nil {
+					return fmt.Errorf("invalid address destination %q: %w", destination.Value, err)
+				}
+			}
+		case EmailForwarderDestinationFile:
+			if !strings.HasPrefix(destination.Value, "/") {
+				return fmt.Errorf("file destination must be an absolute path: %q", destination.Value)
+			}
+		case EmailForwarderDestinationPipe:
+			if !allowPipe {
+				return fmt.Errorf("pipe destinations are disabled on this server: %q", destination.Value)
+			}
+
+			if !strings.HasPrefix(destination.Value, "/") {
+				return fmt.Errorf("pipe destination must start with an absolute path: %q", destination.Value)
+			}
+		case EmailForwarderDestinationSpecial:
+			if destination.Value != EmailForwarderBlackhole && !strings.HasPrefix(destination.Value, EmailForwarderFail) {
+				return fmt.Errorf("invalid special destination: %q", destination.Value)
+			}
+
+			if len(f.Destinations) > 1 {
+				return fmt.Errorf("special destination %q can't be combined with other destinations", destination.Value)
+			}
+		default:
+			return fmt.Errorf("invalid destination type: %q", destination.Type)
+		}
+	}
+
+	return nil
+}
+
+// destinationStrings returns the forwarder's destinations in the raw form DA expects.
+func (f *EmailForwarder) destinationStrings() []string {
+	destinations := make([]string, len(f.Destinations))
+	for i, destination := range f.Destinations {
+		destinations[i] = destination.String()
+	}
+
+	return destinations
+}
+
 // CreateEmailForwarder (user) creates the specified email forwarder.
 func (c *UserContext) CreateEmailForwarder(domain string, user string, emails ...string) error {
-	var response apiGenericResponse
+	if err := c.validateEmailForwarder(domain, user, emails); err != nil {
+		return err
+	}
 
-	body := url.Values{}
-	body.Set("domain", domain)
-	body.Set("email", strings.Join(emails, ","))
-	body.Set("user", user)
+	return c.createEmailForwarder(domain, user, emails)
+}
 
-	if _, err := c.makeRequestOld(http.MethodPost, "API_EMAIL_FORWARDERS?action=create", body, &response); err != nil {
-		return err
+// GetEmailForwarder (user) returns the given email forwarder.
+func (c *UserContext) GetEmailForwarder(domain string, name string) (*EmailForwarder, error) {
+	emailForwarders, err := c.ListEmailForwarders(domain)
+	if err != nil {
+		return nil, err
 	}
 
-	if response.Success != "Forwarder created" {
-		return fmt.Errorf("failed to create email account: %v", response.Result)
+	for _, emailForwarder := range emailForwarders {
+		if strings.EqualFold(emailForwarder.Name, name) {
+			return emailForwarder, nil
+		}
 	}
 
-	return nil
+	return nil, fmt.Errorf("email forwarder not found: %v@%v", name, domain)
 }
 
 // GetEmailForwarders (user) returns an array of email forwarders belonging to the provided domain.
@@ -62,8 +191,89 @@ func (c *UserContext) DeleteEmailForwarders(domain string, names ...string) erro
 	return nil
 }
 
+// ListEmailForwarders (user) returns the email forwarders belonging to the provided domain with typed destinations,
+// sorted by name.
+func (c *UserContext) ListEmailForwarders(domain string) ([]*EmailForwarder, error) {
+	rawEmailForwarders, err := c.GetEmailForwarders(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEmailForwarders(domain, rawEmailForwarders), nil
+}
+
+// SyncEmailForwarders (user) creates, updates and deletes the domain's forwarders so they match the desired list.
+// Every forwarder is validated before any change is made, and must either leave Domain empty or match the domain.
+func (c *UserContext) SyncEmailForwarders(domain string, desired []*EmailForwarder) (*EmailForwarderChanges, error) {
+	allowPipe, err := c.forwarderPipesAllowed(desired...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateEmailForwarders(domain, desired, allowPipe); err != nil {
+		return nil, err
+	}
+
+	current, err := c.ListEmailForwarders(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffEmailForwarders(current, desired)
+
+	// Everything has been validated above, so skip the per-forwarder validation and its session lookups.
+	for _, emailForwarder := range changes.Create {
+		if err = c.createEmailForwarder(domain, emailForwarder.Name, emailForwarder.destinationStrings()); err != nil {
+			return changes, fmt.Errorf("failed to create email forwarder %v: %w", emailForwarder.Name, err)
+		}
+	}
+
+	for _, emailForwarder := range changes.Update {
+		if err = c.updateEmailForwarder(domain, emailForwarder.Name, emailForwarder.destinationStrings()); err != nil {
+			return changes, fmt.Errorf("failed to update email forwarder %v: %w", emailForwarder.Name, err)
+		}
+	}
+
+	if len(changes.Delete) > 0 {
+		if err = c.DeleteEmailForwarders(domain, changes.Delete...); err != nil {
+			return changes, err
+		}
+	}
+
+	return changes, nil
+}
+
 // UpdateEmailForwarder (user) updates the specified email forwarder.
 func (c *UserContext) UpdateEmailForwarder(domain string, user string, emails ...string) error {
+	if err := c.validateEmailForwarder(domain, user, emails); err != nil {
+		return err
+	}
+
+	return c.updateEmailForwarder(domain, user, emails)
+}
+
+// createEmailForwarder (user) creates the email forwarder without validating it first.
+func (c *UserContext) createEmailForwarder(domain string, user string, emails []string) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("domain", domain)
+	body.Set("email", strings.Join(emails, ","))
+	body.Set("user", user)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_EMAIL_FORWARDERS?action=create", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Forwarder created" {
+		return fmt.Errorf("failed to create email account: %v", response.Result)
+	}
+
+	return nil
+}
+
+// updateEmailForwarder (user) updates the email forwarder without validating it first.
+func (c *UserContext) updateEmailForwarder(domain string, user string, emails []string) error {
 	var response apiGenericResponse
 
 	body := url.Values{}
@@ -81,3 +291,164 @@ func (c *UserContext) UpdateEmailForwarder(domain string, user string, emails ..
 
 	return nil
 }
+
+// forwarderPipesAllowed returns whether pipe destinations are allowed. The session is only checked if one of the
+// given forwarders actually uses a pipe.
+func (c *UserContext) forwarderPipesAllowed(emailForwarders ...*EmailForwarder) (bool, error) {
+	for _, emailForwarder := range emailForwarders {
+		if emailForwarder == nil {
+			continue
+		}
+
+		for _, destination := range emailForwarder.Destinations {
+			if destination.Type != EmailForwarderDestinationPipe {
+				continue
+			}
+
+			session, err := c.GetSessionInfo()
+			if err != nil {
+				return false, fmt.Errorf("failed to get session info: %w", err)
+			}
+
+			return session.DirectadminConfig.AllowForwarderPipe, nil
+		}
+	}
+
+	return false, nil
+}
+
+// validateEmailForwarder parses and validates raw destinations before they're sent to DA.
+func (c *UserContext) validateEmailForwarder(domain string, name string, rawDestinations []string) error {
+	emailForwarder := &EmailForwarder{
+		Destinations: make([]EmailForwarderDestination, len(rawDestinations)),
+		Domain:       domain,
+		Name:         name,
+	}
+
+	for i, rawDestination := range rawDestinations {
+		emailForwarder.Destinations[i] = ParseEmailForwarderDestination(rawDestination)
+	}
+
+	allowPipe, err := c.forwarderPipesAllowed(emailForwarder)
+	if err != nil {
+		return err
+	}
+
+	if err = emailForwarder.Validate(allowPipe); err != nil {
+		return fmt.Errorf("invalid email forwarder: %w", err)
+	}
+
+	return nil
+}
+
+// diffEmailForwarders works out which forwarders need creating, updating or deleting to turn current into desired.
+// Destination order is ignored.
+func diffEmailForwarders(current []*EmailForwarder, desired []*EmailForwarder) *EmailForwarderChanges {
+	changes := &EmailForwarderChanges{
+		Create: []*EmailForwarder{},
+		Delete: []string{},
+		Update: []*EmailForwarder{},
+	}
+
+	currentByName := make(map[string]*EmailForwarder, len(current))
+	for _, emailForwarder := range current {
+		currentByName[strings.ToLower(emailForwarder.Name)] = emailForwarder
+	}
+
+	desiredNames := make(map[string]bool, len(desired))
+
+	for _, emailForwarder := range desired {
+		name := strings.ToLower(emailForwarder.Name)
+		desiredNames[name] = true
+
+		existing, ok := currentByName[name]
+		if !ok {
+			changes.Create = append(changes.Create, emailForwarder)
+		} else if !sameForwarderDestinations(existing.Destinations, emailForwarder.Destinations) {
+			changes.Update = append(changes.Update, emailForwarder)
+		}
+	}
+
+	for _, emailForwarder := range current {
+		if !desiredNames[strings.ToLower(emailForwarder.Name)] {
+			changes.Delete = append(changes.Delete, emailForwarder.Name)
+		}
+	}
+
+	return changes
+}
+
+// parseEmailForwarders converts DA's forwarder map into typed forwarders, sorted by name.
+func parseEmailForwarders(domain string, rawEmailForwarders map[string][]string) []*EmailForwarder {
+	emailForwarders := make([]*EmailForwarder, 0, len(rawEmailForwarders))
+
+	for name, rawDestinations := range rawEmailForwarders {
+		emailForwarder := &EmailForwarder{
+			Destinations: make([]EmailForwarderDestination, 0, len(rawDestinations)),
+			Domain:       domain,
+			Name:         name,
+		}
+
+		for _, rawDestination := range rawDestinations {
+			emailForwarder.Destinations = append(emailForwarder.Destinations, ParseEmailForwarderDestination(rawDestination))
+		}
+
+		emailForwarders = append(emailForwarders, emailForwarder)
+	}
+
+	sort.Slice(emailForwarders, func(i, j int) bool {
+		return emailForwarders[i].Name < emailForwarders[j].Name
+	})
+
+	return emailForwarders
+}
+
+func sameForwarderDestinations(a []EmailForwarderDestination, b []EmailForwarderDestination) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	normalize := func(destinations []EmailForwarderDestination) []string {
+		normalized := make([]string, len(destinations))
+		for i, destination := range destinations {
+			normalized[i] = destination.String()
+			if destination.Type == EmailForwarderDestinationAddress {
+				normalized[i] = strings.ToLower(normalized[i])
+			}
+		}
+
+		sort.Strings(normalized)
+
+		return normalized
+	}
+
+	normalizedA := normalize(a)
+	normalizedB := normalize(b)
+
+	for i := range normalizedA {
+		if normalizedA[i] != normalizedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateEmailForwarders checks every forwarder locally, rejecting any that belong to a different domain.
+func validateEmailForwarders(domain string, emailForwarders []*EmailForwarder, allowPipe bool) error {
+	for _, emailForwarder := range emailForwarders {
+		if emailForwarder == nil {
+			return errors.New("invalid email forwarder: forwarder is nil")
+		}
+
+		if emailForwarder.Domain != "" && !strings.EqualFold(emailForwarder.Domain, domain) {
+			return fmt.Errorf("email forwarder %v belongs to %v, not %v", emailForwarder.Name, emailForwarder.Domain, domain)
+		}
+
+		if err := emailForwarder.Validate(allowPipe); err != nil {
+			return fmt.Errorf("invalid email forwarder: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package directadmin
+
+import "testing"
+
+func TestParseEmailForwarderDestination(t *testing.T) {
+	tests := map[string]EmailForwarderDestination{
+		"bob@example.com":         {Type: EmailForwarderDestinationAddress, Value: "bob@example.com"},
+		"| /usr/local/bin/ticket": {Type: EmailForwarderDestinationPipe, Value: "/usr/local/bin/ticket"},
+		"/home/user/mail.log":     {Type: EmailForwarderDestinationFile, Value: "/home/user/mail.log"},
+		":fail: No such user":     {Type: EmailForwarderDestinationSpecial, Value: ":fail: No such user"},
+		":blackhole:":             {Type: EmailForwarderDestinationSpecial, Value: ":blackhole:"},
+	}
+
+	for raw, expected := range tests {
+		if destination := ParseEmailForwarderDestination(raw); destination != expected {
+			t.Errorf("%q: expected %+v, got %+v", raw, expected, destination)
+		}
+	}
+
+	forwarder := EmailForwarder{Name: "support", Destinations: []EmailForwarderDestination{ParseEmailForwarderDestination("|/usr/local/bin/ticket")}}
+	if err := forwarder.Validate(false); err == nil {
+		t.Error("expected pipe to be rejected when pipes are disabled")
+	}
+
+	if err := forwarder.Validate(true); err != nil {
+		t.Errorf("expected pipe to be accepted when pipes are enabled: %v", err)
+	}
+}
+
+func TestDiffEmailForwarders(t *testing.T) {
+	current := []*EmailForwarder{
+		{Name: "info", Destinations: []EmailForwarderDestination{{Type: EmailForwarderDestinationAddress, Value: "a@example.com"}, {Type: EmailForwarderDestinationAddress, Value: "b@example.com"}}},
+		{Name: "old", Destinations: []EmailForwarderDestination{{Type: EmailForwarderDestinationSpecial, Value: ":blackhole:"}}},
+		{Name: "sales", Destinations: []EmailForwarderDestination{{Type: EmailForwarderDestinationAddress, Value: "a@example.com"}}},
+	}
+
+	desired := []*EmailForwarder{
+		{Name: "info", Destinations: []EmailForwarderDestination{{Type: EmailForwarderDestinationAddress, Value: "B@example.com"}, {Type: EmailForwarderDestinationAddress, Value: "a@example.com"}}},
+		{Name: "new", Destinations: []EmailForwarderDestination{{Type: EmailForwarderDestinationAddress, Value: "c@example.com"}}},
+		{Name: "sales", Destinations: []EmailForwarderDestination{{Type: EmailForwarderDestinationAddress, Value: "c@example.com"}}},
+	}
+
+	changes := diffEmailForwarders(current, desired)
+
+	if len(changes.Create) != 1 || changes.Create[0].Name != "new" {
+		t.Errorf("unexpected creates: %+v", changes.Create)
+	}
+
+	if len(changes.Update) != 1 || changes.Update[0].Name != "sales" {
+		t.Errorf("unexpected updates: %+v", changes.Update)
+	}
+
+	if len(changes.Delete) != 1 || changes.Delete[0] != "old" {
+		t.Errorf("unexpected deletes: %+v", changes.Delete)
+	}
+}
+
+func TestParseEmailForwarders(t *testing.T) {
+	emailForwarders := parseEmailForwarders("example.com", map[string][]string{
+		"sales":   {"bob@example.com", "alice"},
+		"devnull": {":blackhole:"},
+	})
+
+	if len(emailForwarders) != 2 || emailForwarders[0].Name != "devnull" || emailForwarders[1].Name != "sales" {
+		t.Fatalf("expected forwarders sorted by name, got %+v", emailForwarders)
+	}
+
+	if sales := emailForwarders[1]; sales.Domain != "example.com" || len(sales.Destinations) != 2 || sales.Destinations[1].Value != "alice" {
+		t.Errorf("unexpected sales forwarder: %+v", sales)
+	}
+
+	if devnull := emailForwarders[0]; devnull.Destinations[0].Type != EmailForwarderDestinationSpecial {
+		t.Errorf("expected a special destination, got %+v", devnull.Destinations[0])
+	}
+}
+
+func TestValidateEmailForwarders(t *testing.T) {
+	destinations := []EmailForwarderDestination{{Type: EmailForwarderDestinationAddress, Value: "bob@example.com"}}
+
+	valid := []*EmailForwarder{
+		{Destinations: destinations, Name: "sales"},
+		{Destinations: destinations, Domain: "EXAMPLE.com", Name: "info"},
+	}
+	if err := validateEmailForwarders("example.com", valid, false); err != nil {
+		t.Errorf("expected forwarders without a domain or with the same domain to pass: %v", err)
+	}
+
+	mismatched := []*EmailForwarder{{Destinations: destinations, Domain: "example.net", Name: "sales"}}
+	if err := validateEmailForwarders("example.com", mismatched, false); err == nil {
+		t.Error("expected a forwarder for another domain to be rejected")
+	}
+
+	if err := validateEmailForwarders("example.com", []*EmailForwarder{nil}, false); err == nil {
+		t.Error("expected a nil forwarder to be rejected")
+	}
+}
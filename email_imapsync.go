@@ -0,0 +1,199 @@
+package directadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	IMAPSyncStatusFailed   = IMAPSyncStatus("failed")
+	IMAPSyncStatusFinished = IMAPSyncStatus("finished")
+	IMAPSyncStatusQueued   = IMAPSyncStatus("queued")
+	IMAPSyncStatusRunning  = IMAPSyncStatus("running")
+
+	IMAPSyncTLSModeNone     = IMAPSyncTLSMode("none")
+	IMAPSyncTLSModeSSL      = IMAPSyncTLSMode("ssl")
+	IMAPSyncTLSModeStartTLS = IMAPSyncTLSMode("starttls")
+)
+
+// imapSyncPollInterval is how often WaitForIMAPSync checks a job's status.
+const imapSyncPollInterval = 10 * time.Second
+
+type (
+	IMAPSyncStatus  string
+	IMAPSyncTLSMode string
+
+	IMAPSyncJob struct {
+		Created        time.Time       `json:"created"`
+		Error          string          `json:"error"`
+		Finished       time.Time       `json:"finished"`
+		ID             string          `json:"id"`
+		Messages       int             `json:"messages"`
+		SourceHost     string          `json:"sourceHost"`
+		SourcePassword string          `json:"sourcePassword,omitempty"`
+		SourcePort     int             `json:"sourcePort"`
+		SourceTLSMode  IMAPSyncTLSMode `json:"sourceTLSMode"`
+		SourceUsername string          `json:"sourceUsername"`
+		Started        time.Time       `json:"started"`
+		Status         IMAPSyncStatus  `json:"status"`
+		// TargetMailbox is the full address of the local mailbox to migrate into.
+		TargetMailbox string `json:"targetMailbox"`
+	}
+
+	IMAPSyncResult struct {
+		Duration time.Duration  `json:"duration"`
+		Error    string         `json:"error"`
+		JobID    string         `json:"jobID"`
+		Log      string         `json:"log"`
+		Messages int            `json:"messages"`
+		Status   IMAPSyncStatus `json:"status"`
+	}
+)
+
+// Success returns whether the migration finished without errors.
+func (r *IMAPSyncResult) Success() bool {
+	return r.Status == IMAPSyncStatusFinished && r.Error == ""
+}
+
+// GetIMAPSyncJob (user) returns the given IMAP migration job.
+func (c *UserContext) GetIMAPSyncJob(jobID string) (*IMAPSyncJob, error) {
+	var job IMAPSyncJob
+
+	if _, err := c.makeRequestNew(http.MethodGet, "imapsync/jobs/"+jobID, nil, &job); err != nil {
+		return nil, fmt.Errorf("failed to get IMAP sync job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetIMAPSyncJobs (user) returns the session user's IMAP migration jobs.
+func (c *UserContext) GetIMAPSyncJobs() ([]*IMAPSyncJob, error) {
+	var jobs []*IMAPSyncJob
+
+	if _, err := c.makeRequestNew(http.MethodGet, "imapsync/jobs", nil, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to get IMAP sync jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// GetIMAPSyncLog (user) returns the imapsync output for the given job.
+func (c *UserContext) GetIMAPSyncLog(jobID string) (string, error) {
+	var response struct {
+		Log string `json:"log"`
+	}
+
+	if _, err := c.makeRequestNew(http.MethodGet, "imapsync/jobs/"+jobID+"/log", nil, &response); err != nil {
+		return "", fmt.Errorf("failed to get IMAP sync log: %w", err)
+	}
+
+	return response.Log, nil
+}
+
+// StartIMAPSync (user) queues a migration from the given source IMAP server into the job's target mailbox. The job's
+// ID, status and creation time are populated from DA's response. Defaults are applied to a copy, so the caller's job is
+// otherwise left as it was and can be retried as-is.
+func (c *UserContext) StartIMAPSync(job *IMAPSyncJob) error {
+	if job == nil {
+		return errors.New("failed to start IMAP sync: job is nil")
+	}
+
+	request, err := prepareIMAPSyncJob(*job)
+	if err != nil {
+		return err
+	}
+
+	session, err := c.GetSessionInfo()
+	if err != nil {
+		return fmt.Errorf("failed to start IMAP sync: %w", err)
+	}
+
+	if !session.ConfigFeatures.IMAPSync {
+		return errors.New("failed to start IMAP sync: IMAP sync isn't enabled on this server")
+	}
+
+	var response IMAPSyncJob
+
+	if _, err = c.makeRequestNew(http.MethodPost, "imapsync/jobs", request, &response); err != nil {
+		return fmt.Errorf("failed to start IMAP sync: %w", err)
+	}
+
+	job.Created = response.Created
+	job.ID = response.ID
+	job.Status = response.Status
+
+	return nil
+}
+
+// WaitForIMAPSync (user) polls the given job until it's no longer queued or running, or until ctx is done. Statuses
+// other than finished and failed are also treated as final, and returned as-is in the result.
+func (c *UserContext) WaitForIMAPSync(ctx context.Context, jobID string) (*IMAPSyncResult, error) {
+	ticker := time.NewTicker(imapSyncPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := c.GetIMAPSyncJob(jobID)
+		if err != nil {
+			return nil, err
+		}
+
+		if job.Status != IMAPSyncStatusQueued && job.Status != IMAPSyncStatusRunning {
+			result := imapSyncResult(job)
+
+			if result.Log, err = c.GetIMAPSyncLog(jobID); err != nil {
+				return result, err
+			}
+
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// imapSyncResult converts a job that's no longer running into a result, without its log.
+func imapSyncResult(job *IMAPSyncJob) *IMAPSyncResult {
+	result := &IMAPSyncResult{
+		Error:    job.Error,
+		JobID:    job.ID,
+		Messages: job.Messages,
+		Status:   job.Status,
+	}
+
+	if !job.Started.IsZero() && !job.Finished.IsZero() {
+		result.Duration = job.Finished.Sub(job.Started)
+	}
+
+	return result
+}
+
+// prepareIMAPSyncJob validates the job and returns it with the TLS mode and port defaulted.
+func prepareIMAPSyncJob(job IMAPSyncJob) (IMAPSyncJob, error) {
+	if job.SourceHost == "" || job.SourceUsername == "" || job.TargetMailbox == "" {
+		return job, errors.New("source host, source username and target mailbox are required")
+	}
+
+	switch job.SourceTLSMode {
+	case "":
+		job.SourceTLSMode = IMAPSyncTLSModeSSL
+	case IMAPSyncTLSModeNone, IMAPSyncTLSModeSSL, IMAPSyncTLSModeStartTLS:
+	default:
+		return job, fmt.Errorf("invalid TLS mode: %v", job.SourceTLSMode)
+	}
+
+	if job.SourcePort == 0 {
+		job.SourcePort = 993
+		if job.SourceTLSMode != IMAPSyncTLSModeSSL {
+			job.SourcePort = 143
+		}
+	}
+
+	return job, nil
+}
@@ -0,0 +1,73 @@
+package directadmin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrepareIMAPSyncJob(t *testing.T) {
+	job := IMAPSyncJob{
+		SourceHost:     "imap.oldhost.tld",
+		SourcePassword: "secret",
+		SourceUsername: "user@example.com",
+		TargetMailbox:  "user@example.com",
+	}
+
+	prepared, err := prepareIMAPSyncJob(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if prepared.SourceTLSMode != IMAPSyncTLSModeSSL || prepared.SourcePort != 993 {
+		t.Errorf("expected SSL on 993, got %v on %d", prepared.SourceTLSMode, prepared.SourcePort)
+	}
+
+	if job.SourceTLSMode != "" || job.SourcePort != 0 || job.SourcePassword != "secret" {
+		t.Errorf("expected the original job to be unchanged, got %+v", job)
+	}
+
+	job.SourceTLSMode = IMAPSyncTLSModeStartTLS
+	if prepared, err = prepareIMAPSyncJob(job); err != nil || prepared.SourcePort != 143 {
+		t.Errorf("expected STARTTLS to default to 143, got %d: %v", prepared.SourcePort, err)
+	}
+
+	job.SourcePort = 1143
+	if prepared, err = prepareIMAPSyncJob(job); err != nil || prepared.SourcePort != 1143 {
+		t.Errorf("expected an explicit port to be kept, got %d: %v", prepared.SourcePort, err)
+	}
+
+	job.SourceTLSMode = "tls1.3"
+	if _, err = prepareIMAPSyncJob(job); err == nil {
+		t.Error("expected an invalid TLS mode to fail validation")
+	}
+
+	if _, err = prepareIMAPSyncJob(IMAPSyncJob{SourceHost: "imap.oldhost.tld"}); err == nil {
+		t.Error("expected a job without a username and target mailbox to fail validation")
+	}
+}
+
+func TestIMAPSyncResult(t *testing.T) {
+	started := time.Unix(1767225600, 0)
+
+	result := imapSyncResult(&IMAPSyncJob{
+		Finished: started.Add(90 * time.Second),
+		ID:       "42",
+		Messages: 1200,
+		Started:  started,
+		Status:   IMAPSyncStatusFinished,
+	})
+
+	if !result.Success() || result.Duration != 90*time.Second || result.JobID != "42" || result.Messages != 1200 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	result = imapSyncResult(&IMAPSyncJob{Error: "authentication failed", ID: "43", Status: IMAPSyncStatusFailed})
+	if result.Success() || result.Duration != 0 || result.Error != "authentication failed" {
+		t.Errorf("unexpected failed result: %+v", result)
+	}
+
+	// Statuses WaitForIMAPSync doesn't know about are final, but never successful.
+	if result = imapSyncResult(&IMAPSyncJob{ID: "44", Status: "cancelled"}); result.Success() {
+		t.Errorf("expected a cancelled job not to succeed: %+v", result)
+	}
+}
@@ -0,0 +1,124 @@
+package directadmin
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+type (
+	rawEmailAccount struct {
+		Login struct {
+			IMAP string `json:"imap"`
+			POP3 string `json:"pop3"`
+			SMTP string `json:"smtp"`
+		} `json:"login"`
+		Sent      rawEmailSent `json:"sent"`
+		Suspended string       `json:"suspended"`
+		Usage     struct {
+			DiskQuota    string `json:"quota"`
+			DiskUsage    string `json:"usage"`
+			MessageCount string `json:"messages"`
+		} `json:"usage"`
+		Username string `json:"account"`
+	}
+
+	// rawEmailSent handles DA's "sent" field, which is either a plain count or an object depending on which usage
+	// endpoint we hit.
+	rawEmailSent struct {
+		PerDay    map[string]string `json:"per_day"`
+		SendLimit string            `json:"send_limit"`
+		Sent      string            `json:"sent"`
+	}
+)
+
+func (r *rawEmailSent) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		var raw struct {
+			PerDay    map[string]any `json:"per_day"`
+			SendLimit any            `json:"send_limit"`
+			Sent      any            `json:"sent"`
+		}
+
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+
+		r.PerDay = make(map[string]string, len(raw.PerDay))
+		for day, count := range raw.PerDay {
+			r.PerDay[day] = cast.ToString(count)
+		}
+
+		r.SendLimit = cast.ToString(raw.SendLimit)
+		r.Sent = cast.ToString(raw.Sent)
+
+		return nil
+	}
+
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Sent = cast.ToString(raw)
+
+	return nil
+}
+
+// translate returns an EmailAccount object.
+func (r *rawEmailAccount) translate(domain string) EmailAccount {
+	emailAccount := EmailAccount{
+		DiskQuota:    cast.ToInt(r.Usage.DiskQuota),
+		DiskUsage:    cast.ToInt(r.Usage.DiskUsage),
+		Domain:       domain,
+		MessageCount: cast.ToInt(r.Usage.MessageCount),
+		SendQuota:    cast.ToInt(r.Sent.SendLimit),
+		SendUsage:    cast.ToInt(r.Sent.Sent),
+		Suspended:    parseOnOff(r.Suspended),
+		Username:     r.Username,
+	}
+
+	if emailAccount.DiskQuota > 0 {
+		emailAccount.DiskQuotaPercent = float64(emailAccount.DiskUsage) / float64(emailAccount.DiskQuota) * 100
+	}
+
+	// Use the most recent login across all protocols.
+	for protocol, rawTimestamp := range map[string]string{"imap": r.Login.IMAP, "pop3": r.Login.POP3, "smtp": r.Login.SMTP} {
+		timestamp := cast.ToInt64(rawTimestamp)
+		if timestamp <= 0 {
+			continue
+		}
+
+		lastLogin := time.Unix(timestamp, 0)
+		if lastLogin.After(emailAccount.LastLogin) {
+			emailAccount.LastLogin = lastLogin
+			emailAccount.LastLoginProtocol = protocol
+		}
+	}
+
+	for rawDay, rawCount := range r.Sent.PerDay {
+		day, err := time.Parse(time.DateOnly, strings.TrimSpace(rawDay))
+		if err != nil {
+			continue
+		}
+
+		emailAccount.SentPerDay = append(emailAccount.SentPerDay, EmailSentDay{
+			Count: cast.ToInt(rawCount),
+			Day:   day,
+		})
+	}
+
+	sort.Slice(emailAccount.SentPerDay, func(i, j int) bool {
+		return emailAccount.SentPerDay[i].Day.Before(emailAccount.SentPerDay[j].Day)
+	})
+
+	// Older DA versions only return the daily breakdown, so fall back to the newest day's count.
+	if r.Sent.Sent == "" && len(emailAccount.SentPerDay) > 0 {
+		emailAccount.SendUsage = emailAccount.SentPerDay[len(emailAccount.SentPerDay)-1].Count
+	}
+
+	return emailAccount
+}
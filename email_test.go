@@ -0,0 +1,44 @@
+package directadmin
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const daEmailAccounts = `{"emails":{"info":{"account":"info"},"alice":{"account":"alice","login":{"imap":"1760000000","pop3":"","smtp":"1760100000"},"sent":{"per_day":{"2025-10-09":4,"2025-10-08":"7"},"send_limit":"200","sent":"11"},"suspended":"no","usage":{"messages":"352","quota":"1000","usage":"250"}},"bob":{"account":"bob","sent":"3","suspended":"yes","usage":{"quota":"0","usage":"10"}},"carol":{"account":"carol","sent":{"per_day":{"2020-01-02":"5","2020-01-01":"9"}}}}}`
+
+func TestEmailAccountTranslation(t *testing.T) {
+	var raw struct {
+		EmailAccounts map[string]rawEmailAccount `json:"emails"`
+	}
+
+	if err := json.Unmarshal([]byte(daEmailAccounts), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	rawAlice := raw.EmailAccounts["alice"]
+	alice := rawAlice.translate("example.com")
+	if alice.SendUsage != 11 || alice.SendQuota != 200 || alice.MessageCount != 352 || alice.DiskQuotaPercent != 25 {
+		t.Fatalf("unexpected usage for alice: %+v", alice)
+	}
+
+	if alice.LastLoginProtocol != "smtp" || alice.LastLogin.Unix() != 1760100000 {
+		t.Fatalf("unexpected last login for alice: %v via %v", alice.LastLogin, alice.LastLoginProtocol)
+	}
+
+	if len(alice.SentPerDay) != 2 || alice.SentPerDay[0].Count != 7 || alice.SentPerDay[1].Count != 4 {
+		t.Fatalf("unexpected sent per day for alice: %+v", alice.SentPerDay)
+	}
+
+	rawBob := raw.EmailAccounts["bob"]
+	bob := rawBob.translate("example.com")
+	if bob.SendUsage != 3 || bob.DiskQuotaPercent != 0 || !bob.Suspended || !bob.LastLogin.IsZero() {
+		t.Fatalf("unexpected usage for bob: %+v", bob)
+	}
+
+	// Without a sent total, the newest day's count is used however old it is.
+	rawCarol := raw.EmailAccounts["carol"]
+	if carol := rawCarol.translate("example.com"); carol.SendUsage != 5 {
+		t.Fatalf("expected carol's send usage to be 5, got %d", carol.SendUsage)
+	}
+}
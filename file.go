@@ -2,15 +2,46 @@ package directadmin
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+)
+
+// maxTextFileBytes caps the size of files ReadTextFile will load, as it's meant for editing config files and the like.
+const maxTextFileBytes = 10 << 20
+
+const (
+	FileSortModified = FileSortField("modified")
+	FileSortName     = FileSortField("name")
+	FileSortSize     = FileSortField("size")
+	FileSortType     = FileSortField("type")
+)
+
+type (
+	FileSortField string
+
+	// ListDirectoryOptions configures ListDirectory. Zero values list every entry sorted by name.
+	ListDirectoryOptions struct {
+		// DirectoriesFirst lists directories before other entries, each sorted by SortBy.
+		DirectoriesFirst bool
+		// Page is the 1-indexed page to return when PageSize is set. Pages are cut on the client, see ListDirectory.
+		Page           int
+		PageSize       int
+		SortBy         FileSortField
+		SortDescending bool
+	}
 )
 
 type FileMetadata struct {
@@ -33,6 +64,29 @@ type FileMetadata struct {
 	User     string `json:"user"`
 }
 
+// CopyPath (user) copies the given file or directory to the new destination.
+func (c *UserContext) CopyPath(source string, destination string, overwrite bool) error {
+	if source == "" || destination == "" {
+		return errors.New("no source or destination provided")
+	}
+
+	body := struct {
+		Destination string `json:"destination"`
+		Overwrite   bool   `json:"overwrite"`
+		Source      string `json:"source"`
+	}{
+		Destination: normalizeFilePath(destination),
+		Overwrite:   overwrite,
+		Source:      normalizeFilePath(source),
+	}
+
+	if _, err := c.makeRequestNew(http.MethodPost, "filemanager-actions/copy", body, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateArchive (user) creates a zip of the given files on the server.
 //
 // The destination path is relative by default.
@@ -61,9 +115,9 @@ func (c *UserContext) CreateArchive(destinationPath string, sources ...string) e
 }
 
 // CreateDirectory (user) creates the given path, including any missing parent directories.
-func (c *UserContext) CreateDirectory(path string) error {
+func (c *UserContext) CreateDirectory(dirPath string) error {
 	body := map[string]string{
-		"path": path,
+		"path": normalizeFilePath(dirPath),
 	}
 
 	if _, err := c.makeRequestNew(http.MethodPost, "filemanager-actions/mkdir", body, nil); err != nil {
@@ -73,6 +127,28 @@ func (c *UserContext) CreateDirectory(path string) error {
 	return nil
 }
 
+// CreateSymlink (user) creates a symbolic link at linkPath pointing to target. The target is stored as given, so it may
+// be relative to the link's directory.
+func (c *UserContext) CreateSymlink(target string, linkPath string) error {
+	if target == "" || linkPath == "" {
+		return errors.New("no target or link path provided")
+	}
+
+	body := struct {
+		Path   string `json:"path"`
+		Target string `json:"target"`
+	}{
+		Path:   normalizeFilePath(linkPath),
+		Target: target,
+	}
+
+	if _, err := c.makeRequestNew(http.MethodPost, "filemanager-actions/symlink", body, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteFiles (user) deletes all the specified files for the session user.
 func (c *UserContext) DeleteFiles(skipTrash bool, files ...string) error {
 	if len(files) == 0 {
@@ -82,15 +158,12 @@ func (c *UserContext) DeleteFiles(skipTrash bool, files ...string) error {
 	normalized := make([]string, len(files))
 
 	for i, f := range files {
-		if len(f) == 0 {
-			return fmt.Errorf("empty file path provided for file %d", i+1)
-		}
-
-		if f[0] != '/' {
-			f = "/" + f
+		normalizedPath, err := normalizeDestructivePath(f)
+		if err != nil {
+			return fmt.Errorf("file %d: %w", i+1, err)
 		}
 
-		normalized[i] = f
+		normalized[i] = normalizedPath
 	}
 
 	body := struct {
@@ -110,13 +183,25 @@ func (c *UserContext) DeleteFiles(skipTrash bool, files ...string) error {
 
 // DownloadFile (user) downloads the given file path from the server.
 func (c *UserContext) DownloadFile(filePath string) ([]byte, error) {
-	return c.makeRequestNew(http.MethodGet, "filemanager/download?path="+filePath, nil, nil)
+	return c.makeRequestNew(http.MethodGet, "filemanager/download?path="+url.QueryEscape(normalizeFilePath(filePath)), nil, nil)
+}
+
+// DownloadFileTo (user) streams the given file from the server to the writer without holding it in memory, returning
+// the number of bytes written. The progress function is optional.
+func (c *UserContext) DownloadFileTo(filePath string, writer io.Writer, progress ProgressFunc) (int64, error) {
+	written, err := c.makeRequestNewStream("filemanager/download?path="+url.QueryEscape(normalizeFilePath(filePath)), writer, progress)
+	if err != nil {
+		return written, fmt.Errorf("failed to download file: %w", err)
+	}
+
+	return written, nil
 }
 
-// DownloadFileToDisk (user) wraps DownloadFile and writes the output to the given path.
+// DownloadFileToDisk (user) wraps DownloadFileTo and writes the output to the given path.
 func (c *UserContext) DownloadFileToDisk(filePath string, outputPath string) error {
-	return writeToDisk(outputPath, func() ([]byte, error) {
-		return c.DownloadFile(filePath)
+	return writeToDisk(outputPath, func(writer io.Writer) error {
+		_, err := c.DownloadFileTo(filePath, writer, nil)
+		return err
 	})
 }
 
@@ -126,15 +211,8 @@ func (c *UserContext) ExtractArchive(destinationDir string, source string, merge
 		return errors.New("no destination directory or source provided")
 	}
 
-	// Prepend / to the filePath if necessary.
-	if destinationDir[0] != '/' {
-		destinationDir = "/" + destinationDir
-	}
-
-	// Prepend / to the file if necessary.
-	if source[0] != '/' {
-		source = "/" + source
-	}
+	destinationDir = normalizeFilePath(destinationDir)
+	source = normalizeFilePath(source)
 
 	body := struct {
 		DestinationDir    string `json:"destinationDir"`
@@ -157,15 +235,63 @@ func (c *UserContext) ExtractArchive(destinationDir string, source string, merge
 func (c *UserContext) GetFileMetadata(filePath string) (*FileMetadata, error) {
 	var response *FileMetadata
 
-	if _, err := c.makeRequestNew(http.MethodGet, "filemanager/metadata?path="+filePath, nil, &response); err != nil {
+	if _, err := c.makeRequestNew(http.MethodGet, "filemanager/metadata?path="+url.QueryEscape(normalizeFilePath(filePath)), nil, &response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
-// MovePath (user) moves the given file or directory to the new destination.
+// ListDirectory (user) returns the entries in the given directory, sorted and paginated according to the options.
+//
+// The file manager always returns the whole directory, so sorting and paging happen on the client. Paging saves the
+// caller from handling every entry, but not the time or memory spent fetching a large directory.
+func (c *UserContext) ListDirectory(dirPath string, opts ListDirectoryOptions) ([]*FileMetadata, error) {
+	if opts.Page < 0 || opts.PageSize < 0 {
+		return nil, errors.New("page and page size can't be negative")
+	}
+
+	switch opts.SortBy {
+	case "":
+		opts.SortBy = FileSortName
+	case FileSortModified, FileSortName, FileSortSize, FileSortType:
+	default:
+		return nil, fmt.Errorf("invalid sort field: %v", opts.SortBy)
+	}
+
+	var entries []*FileMetadata
+
+	if _, err := c.makeRequestNew(http.MethodGet, "filemanager/list?path="+url.QueryEscape(normalizeFilePath(dirPath)), nil, &entries); err != nil {
+		return nil, err
+	}
+
+	sortFileMetadata(entries, opts)
+
+	if opts.PageSize == 0 {
+		return entries, nil
+	}
+
+	start := (max(opts.Page, 1) - 1) * opts.PageSize
+	if start >= len(entries) {
+		return []*FileMetadata{}, nil
+	}
+
+	return entries[start:min(start+opts.PageSize, len(entries))], nil
+}
+
+// MovePath (user) moves the given file or directory to the new destination. Neither path may resolve to the home
+// directory itself.
 func (c *UserContext) MovePath(source string, destination string, overwrite bool) error {
+	source, err := normalizeDestructivePath(source)
+	if err != nil {
+		return fmt.Errorf("invalid source: %w", err)
+	}
+
+	destination, err = normalizeDestructivePath(destination)
+	if err != nil {
+		return fmt.Errorf("invalid destination: %w", err)
+	}
+
 	body := struct {
 		Destination string `json:"destination"`
 		Overwrite   bool   `json:"overwrite"`
@@ -176,50 +302,93 @@ func (c *UserContext) MovePath(source string, destination string, overwrite bool
 		Source:      source,
 	}
 
-	if _, err := c.makeRequestNew(http.MethodPost, "filemanager-actions/move", body, nil); err != nil {
+	if _, err = c.makeRequestNew(http.MethodPost, "filemanager-actions/move", body, nil); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// UploadFile uploads the provided byte data as a file for the session user.
-func (c *UserContext) UploadFile(uploadToPath string, fileData []byte, overwrite bool) error {
-	// Prepend / to uploadToPath if it doesn't exist.
-	if uploadToPath[0] != '/' {
-		uploadToPath = "/" + uploadToPath
+// ReadTextFile (user) returns the contents of the given text file. Files over 10MB, directories and files that aren't
+// valid UTF-8 are refused.
+func (c *UserContext) ReadTextFile(filePath string) (string, error) {
+	filePath = normalizeFilePath(filePath)
+
+	metadata, err := c.GetFileMetadata(filePath)
+	if err != nil {
+		return "", err
 	}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	if metadata.isDir() {
+		return "", fmt.Errorf("%v is a directory", filePath)
+	}
 
-	part, err := writer.CreateFormFile("file", filepath.Base(uploadToPath))
-	if err != nil {
-		return fmt.Errorf("creating form file: %w", err)
+	if metadata.SizeBytes > maxTextFileBytes {
+		return "", fmt.Errorf("%v is %d bytes, which is too large to edit", filePath, metadata.SizeBytes)
+	}
+
+	var contents bytes.Buffer
+
+	if _, err = c.DownloadFileTo(filePath, &contents, nil); err != nil {
+		return "", err
+	}
+
+	if !utf8.Valid(contents.Bytes()) {
+		return "", fmt.Errorf("%v isn't a text file", filePath)
 	}
 
-	if _, err = part.Write(fileData); err != nil {
-		return fmt.Errorf("writing file data: %w", err)
+	return contents.String(), nil
+}
+
+// SetPermissions (user) sets the permission bits of the given path, and of everything beneath it if recursive is set.
+//
+// There's no counterpart for ownership, as the file manager doesn't let users change who owns their files.
+func (c *UserContext) SetPermissions(filePath string, mode fs.FileMode, recursive bool) error {
+	if filePath == "" {
+		return errors.New("no path provided")
 	}
 
-	if err = writer.Close(); err != nil {
-		return fmt.Errorf("finalizing form data: %w", err)
+	if mode&^(fs.ModePerm|fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky) != 0 {
+		return fmt.Errorf("invalid permissions: %v", mode)
 	}
 
-	overwriteQuery := "false"
-	if overwrite {
-		overwriteQuery = "true"
+	// A recursive change of the home directory would reach every file the user has.
+	if recursive {
+		if _, err := normalizeDestructivePath(filePath); err != nil {
+			return err
+		}
 	}
 
-	// Now use this content type which includes the boundary.
-	if _, err = c.uploadFile(http.MethodPost, "/api/filemanager-actions/upload?dir="+filepath.Dir(uploadToPath)+"&overwrite="+overwriteQuery+"&name="+filepath.Base(uploadToPath), body.Bytes(), nil, writer.FormDataContentType()); err != nil {
+	body := struct {
+		Mode      string `json:"mode"`
+		Path      string `json:"path"`
+		Recursive bool   `json:"recursive"`
+	}{
+		Mode:      formatUnixMode(mode),
+		Path:      normalizeFilePath(filePath),
+		Recursive: recursive,
+	}
+
+	if _, err := c.makeRequestNew(http.MethodPost, "filemanager-actions/chmod", body, nil); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// UploadFileFromDisk (user) uploads the provided file for the session user.
+// UploadFile uploads the provided byte data as a file for the session user.
+func (c *UserContext) UploadFile(uploadToPath string, fileData []byte, overwrite bool) error {
+	return c.UploadFileFrom(uploadToPath, bytes.NewReader(fileData), int64(len(fileData)), overwrite, nil)
+}
+
+// UploadFileFrom (user) uploads the reader's contents as a file for the session user, streaming the multipart body
+// rather than buffering it in memory. The size is used to report progress and to check that the whole file was sent;
+// pass -1 if it isn't known. The progress function is optional.
+func (c *UserContext) UploadFileFrom(uploadToPath string, reader io.Reader, size int64, overwrite bool, progress ProgressFunc) error {
+	return c.uploadFile(context.Background(), uploadToPath, reader, size, overwrite, progress)
+}
+
+// UploadFileFromDisk (user) uploads the provided file for the session user, streaming it from disk.
 //
 // Example: UploadFileFromDisk("/domains/domain.tld/public_html/file.zip", "file.zip").
 func (c *UserContext) UploadFileFromDisk(uploadToPath string, localFilePath string, overwrite bool) error {
@@ -236,22 +405,166 @@ func (c *UserContext) UploadFileFromDisk(uploadToPath string, localFilePath stri
 	}
 	defer file.Close()
 
-	fileData, err := io.ReadAll(file)
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return c.UploadFileFrom(uploadToPath, file, fileInfo.Size(), overwrite, nil)
+}
+
+// WriteTextFile (user) replaces the contents of the given text file, creating it if it doesn't exist. An existing
+// file keeps its permissions.
+func (c *UserContext) WriteTextFile(filePath string, contents string) error {
+	filePath = normalizeFilePath(filePath)
+
+	existing, existingErr := c.GetFileMetadata(filePath)
+
+	if err := c.UploadFile(filePath, []byte(contents), true); err != nil {
+		return err
+	}
+
+	if existingErr != nil {
+		return nil
+	}
+
+	updated, err := c.GetFileMetadata(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return err
+	}
+
+	if updated.UnixMode != existing.UnixMode {
+		return c.SetPermissions(filePath, unixModeToFileMode(existing.UnixMode), false)
 	}
 
-	return c.UploadFile(uploadToPath, fileData, overwrite)
+	return nil
 }
 
-// writeToDisk wraps a function that returns data and writes the output to the given path.
-// It handles file creation, cleanup on failure, and proper error handling.
-func writeToDisk(outputPath string, dataFunc func() ([]byte, error)) error {
+func (f *FileMetadata) isDir() bool {
+	return f.Type == "dir" || f.Type == "directory"
+}
+
+func (f *FileMetadata) isSymlink() bool {
+	return f.Type == "link" || f.Type == "symlink" || f.Symlink.Target != ""
+}
+
+// formatUnixMode returns the mode as the octal string chmod takes, including the setuid, setgid and sticky bits.
+func formatUnixMode(mode fs.FileMode) string {
+	unixMode := uint32(mode.Perm())
+
+	if mode&fs.ModeSetuid != 0 {
+		unixMode |= 0o4000
+	}
+
+	if mode&fs.ModeSetgid != 0 {
+		unixMode |= 0o2000
+	}
+
+	if mode&fs.ModeSticky != 0 {
+		unixMode |= 0o1000
+	}
+
+	return fmt.Sprintf("%04o", unixMode)
+}
+
+// normalizeFilePath prepends / to the given path if necessary, as the file manager treats all paths as relative to
+// the user's home directory. The path is also cleaned, so "admin/" and "/admin" refer to the same directory.
+func normalizeFilePath(filePath string) string {
+	return path.Clean("/" + filePath)
+}
+
+// uploadFile (user) is UploadFileFrom, aborting the upload if ctx is done.
+func (c *UserContext) uploadFile(ctx context.Context, uploadToPath string, reader io.Reader, size int64, overwrite bool, progress ProgressFunc) error {
+	uploadToPath = normalizeFilePath(uploadToPath)
+
+	body, contentType, contentLength := pipeMultipart("file", filepath.Base(uploadToPath), withProgressReader(reader, size, progress), size)
+
+	query := url.Values{}
+	query.Set("dir", filepath.Dir(uploadToPath))
+	query.Set("name", filepath.Base(uploadToPath))
+	query.Set("overwrite", strconv.FormatBool(overwrite))
+
+	if _, err := c.uploadStream(ctx, http.MethodPost, "/api/filemanager-actions/upload?"+query.Encode(), body, contentLength, nil, contentType); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeDestructivePath normalises the given path for a destructive action, refusing empty paths and any path that
+// resolves to the home directory itself, such as "." or "..".
+func normalizeDestructivePath(filePath string) (string, error) {
+	if filePath == "" {
+		return "", errors.New("empty file path provided")
+	}
+
+	normalized := normalizeFilePath(filePath)
+	if normalized == "/" {
+		return "", fmt.Errorf("refusing to act on the home directory (%q)", filePath)
+	}
+
+	return normalized, nil
+}
+
+// sortFileMetadata sorts the entries in place according to the listing options, falling back to the name for ties.
+func sortFileMetadata(entries []*FileMetadata, opts ListDirectoryOptions) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+
+		if opts.DirectoriesFirst && a.isDir() != b.isDir() {
+			return a.isDir()
+		}
+
+		if opts.SortDescending {
+			a, b = b, a
+		}
+
+		switch opts.SortBy {
+		case FileSortModified:
+			if !a.ModifyTime.Equal(b.ModifyTime) {
+				return a.ModifyTime.Before(b.ModifyTime)
+			}
+		case FileSortSize:
+			if a.SizeBytes != b.SizeBytes {
+				return a.SizeBytes < b.SizeBytes
+			}
+		case FileSortType:
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+		}
+
+		return a.Name < b.Name
+	})
+}
+
+// unixModeToFileMode converts a mode as DA reports it, e.g. 0o4755, into an fs.FileMode.
+func unixModeToFileMode(unixMode int) fs.FileMode {
+	mode := fs.FileMode(unixMode) & fs.ModePerm
+
+	if unixMode&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+
+	if unixMode&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+
+	if unixMode&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+
+	return mode
+}
+
+// writeToDisk creates the given path and passes it to writeFunc to stream data into. It handles file creation, and
+// removes the file if writing fails.
+func writeToDisk(outputPath string, writeFunc func(writer io.Writer) error) (err error) {
 	if outputPath == "" {
 		return errors.New("no file path provided")
 	}
 
-	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+	if _, err = os.Stat(outputPath); !os.IsNotExist(err) {
 		return fmt.Errorf("file already exists: %s", outputPath)
 	}
 
@@ -259,25 +572,22 @@ func writeToDisk(outputPath string, dataFunc func() ([]byte, error)) error {
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer file.Close()
 
 	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("error closing file: %w", closeErr)
+		}
+
 		if err != nil {
-			fileRemoveErr := os.Remove(outputPath)
-			if fileRemoveErr != nil {
+			if fileRemoveErr := os.Remove(outputPath); fileRemoveErr != nil {
 				err = fmt.Errorf("%w: %w", err, fileRemoveErr)
 			}
 		}
 	}()
 
-	data, err := dataFunc()
-	if err != nil {
+	if err = writeFunc(file); err != nil {
 		return err
 	}
 
-	if _, err = file.Write(data); err != nil {
-		return fmt.Errorf("error writing to file: %w", err)
-	}
-
 	return nil
 }
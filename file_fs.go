@@ -0,0 +1,455 @@
+package directadmin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"time"
+)
+
+var (
+	_ fs.ReadDirFS  = (*FileSystem)(nil)
+	_ fs.ReadFileFS = (*FileSystem)(nil)
+	_ fs.StatFS     = (*FileSystem)(nil)
+)
+
+type (
+	// FileSystem is a read-only fs.FS over the session user's files, rooted at a directory in their home. Files are
+	// only downloaded once they're read, and are streamed rather than held in memory.
+	FileSystem struct {
+		ctx  *UserContext
+		root string
+	}
+
+	// WritableFileSystem extends FileSystem with methods to change the session user's files.
+	WritableFileSystem struct {
+		*FileSystem
+	}
+
+	remoteDir struct {
+		entries []fs.DirEntry
+		info    *remoteFileInfo
+		listed  bool
+		name    string
+		system  *FileSystem
+	}
+
+	remoteFile struct {
+		info   *remoteFileInfo
+		name   string
+		offset int64
+		// reader streams the file from offset onwards, and is nil until the file is read or after seeking.
+		reader *io.PipeReader
+		system *FileSystem
+	}
+
+	remoteFileInfo struct {
+		metadata *FileMetadata
+		name     string
+	}
+
+	remoteFileWriter struct {
+		done   chan error
+		writer *io.PipeWriter
+	}
+
+	// skipWriter discards the first skip bytes written to it, then passes the rest through.
+	skipWriter struct {
+		skip   int64
+		writer io.Writer
+	}
+)
+
+// FS (user) returns a read-only fs.FS over the session user's files, rooted at the given directory in their home.
+//
+// Stat and Open follow symlinks, while ReadDir reports them as symlinks, matching the os package.
+func (c *UserContext) FS(root string) *FileSystem {
+	return &FileSystem{ctx: c, root: normalizeFilePath(root)}
+}
+
+// WritableFS (user) is like FS, but the returned filesystem can also change the session user's files.
+func (c *UserContext) WritableFS(root string) *WritableFileSystem {
+	return &WritableFileSystem{FileSystem: c.FS(root)}
+}
+
+// Open opens the named file or directory.
+func (f *FileSystem) Open(name string) (fs.File, error) {
+	info, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &remoteDir{info: info, name: name, system: f}, nil
+	}
+
+	return &remoteFile{info: info, name: name, system: f}, nil
+}
+
+// ReadDir returns the named directory's entries sorted by filename, without following symlinks.
+func (f *FileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entries, err := f.ctx.ListDirectory(f.remotePath(name), ListDirectoryOptions{SortBy: FileSortName})
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: mapFileManagerError(err)}
+	}
+
+	dirEntries := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		dirEntries = append(dirEntries, fs.FileInfoToDirEntry(&remoteFileInfo{metadata: entry, name: entry.Name}))
+	}
+
+	return dirEntries, nil
+}
+
+// ReadFile returns the named file's contents.
+func (f *FileSystem) ReadFile(name string) ([]byte, error) {
+	info, err := f.stat("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+
+	var contents bytes.Buffer
+
+	if _, err = f.ctx.DownloadFileTo(f.remotePath(name), &contents, nil); err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: mapFileManagerError(err)}
+	}
+
+	return contents.Bytes(), nil
+}
+
+// Stat returns the named file's info, following symlinks.
+func (f *FileSystem) Stat(name string) (fs.FileInfo, error) {
+	info, err := f.stat("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// remotePath returns the file manager path for the given fs path, which must be valid.
+func (f *FileSystem) remotePath(name string) string {
+	return path.Join(f.root, name)
+}
+
+// stat returns the named file's info, resolving symlinks so the info describes the file they point to.
+func (f *FileSystem) stat(op string, name string) (*remoteFileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	metadata, err := f.ctx.GetFileMetadata(f.remotePath(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: mapFileManagerError(err)}
+	}
+
+	if metadata.isSymlink() && metadata.Symlink.Resolved != "" {
+		if metadata, err = f.ctx.GetFileMetadata(metadata.Symlink.Resolved); err != nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: mapFileManagerError(err)}
+		}
+	}
+
+	return &remoteFileInfo{metadata: metadata, name: path.Base(name)}, nil
+}
+
+// Chmod sets the named file's permission bits.
+func (w *WritableFileSystem) Chmod(name string, mode fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if err := w.ctx.SetPermissions(w.remotePath(name), mode, false); err != nil {
+		return &fs.PathError{Op: "chmod", Path: name, Err: mapFileManagerError(err)}
+	}
+
+	return nil
+}
+
+// Create returns a writer that streams to the named file as it's written, replacing the file if it exists. The file
+// is only complete once Close has returned without an error.
+func (w *WritableFileSystem) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	writer := &remoteFileWriter{done: make(chan error, 1), writer: pipeWriter}
+
+	go func() {
+		err := w.ctx.UploadFileFrom(w.remotePath(name), pipeReader, -1, true, nil)
+		if err != nil {
+			err = &fs.PathError{Op: "create", Path: name, Err: mapFileManagerError(err)}
+		}
+
+		// Fail any further writes, e.g. if the upload ended early.
+		pipeReader.CloseWithError(err)
+		writer.done <- err
+	}()
+
+	return writer, nil
+}
+
+// MkdirAll creates the named directory, along with any missing parents.
+func (w *WritableFileSystem) MkdirAll(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if err := w.ctx.CreateDirectory(w.remotePath(name)); err != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: mapFileManagerError(err)}
+	}
+
+	return nil
+}
+
+// RemoveAll deletes the named file, or the named directory and everything in it, skipping the trash.
+func (w *WritableFileSystem) RemoveAll(name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if err := w.ctx.DeleteFiles(true, w.remotePath(name)); err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: mapFileManagerError(err)}
+	}
+
+	return nil
+}
+
+// Rename moves the old path to the new one, replacing anything already there.
+func (w *WritableFileSystem) Rename(oldName string, newName string) error {
+	if !fs.ValidPath(oldName) || !fs.ValidPath(newName) {
+		return &fs.PathError{Op: "rename", Path: oldName, Err: fs.ErrInvalid}
+	}
+
+	if err := w.ctx.MovePath(w.remotePath(oldName), w.remotePath(newName), true); err != nil {
+		return &fs.PathError{Op: "rename", Path: oldName, Err: mapFileManagerError(err)}
+	}
+
+	return nil
+}
+
+// WriteFile writes the data to the named file, replacing it if it exists. If perm isn't 0, the file's permissions are
+// set to it afterward.
+func (w *WritableFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if err := w.ctx.UploadFile(w.remotePath(name), data, true); err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: mapFileManagerError(err)}
+	}
+
+	if perm != 0 {
+		return w.Chmod(name, perm)
+	}
+
+	return nil
+}
+
+func (d *remoteDir) Close() error {
+	return nil
+}
+
+func (d *remoteDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir lists the directory on the first call, then returns its entries following the fs.ReadDirFile contract.
+func (d *remoteDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	if !d.listed {
+		entries, err := d.system.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+
+		d.entries = entries
+		d.listed = true
+	}
+
+	if count <= 0 {
+		entries := d.entries
+		d.entries = nil
+
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	count = min(count, len(d.entries))
+	entries := d.entries[:count]
+	d.entries = d.entries[count:]
+
+	return entries, nil
+}
+
+func (d *remoteDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (r *remoteFile) Close() error {
+	if r.reader != nil {
+		r.reader.Close()
+		r.reader = nil
+	}
+
+	return nil
+}
+
+// Read streams the file, starting the download from the current offset on the first read after opening or seeking.
+func (r *remoteFile) Read(p []byte) (int, error) {
+	if r.offset >= r.info.Size() {
+		return 0, io.EOF
+	}
+
+	if r.reader == nil {
+		r.reader = r.download()
+	}
+
+	n, err := r.reader.Read(p)
+	r.offset += int64(n)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = &fs.PathError{Op: "read", Path: r.name, Err: err}
+	}
+
+	return n, err
+}
+
+// Seek moves the offset for the next read. The file manager can't download part of a file, so seeking anywhere but the
+// current offset restarts the download, skipping everything before the new offset.
+func (r *remoteFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.info.Size()
+	}
+
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: r.name, Err: fs.ErrInvalid}
+	}
+
+	if offset != r.offset {
+		r.Close()
+		r.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (r *remoteFile) Stat() (fs.FileInfo, error) {
+	return r.info, nil
+}
+
+// download starts streaming the file in the background, discarding everything before the current offset.
+func (r *remoteFile) download() *io.PipeReader {
+	pipeReader, pipeWriter := io.Pipe()
+	skip := r.offset
+
+	go func() {
+		writer := io.Writer(pipeWriter)
+		if skip > 0 {
+			writer = &skipWriter{skip: skip, writer: pipeWriter}
+		}
+
+		_, err := r.system.ctx.DownloadFileTo(r.system.remotePath(r.name), writer, nil)
+		pipeWriter.CloseWithError(mapFileManagerError(err))
+	}()
+
+	return pipeReader
+}
+
+func (i *remoteFileInfo) IsDir() bool {
+	return i.Mode().IsDir()
+}
+
+func (i *remoteFileInfo) ModTime() time.Time {
+	return i.metadata.ModifyTime
+}
+
+func (i *remoteFileInfo) Mode() fs.FileMode {
+	mode := unixModeToFileMode(i.metadata.UnixMode)
+
+	// A symlink to a directory may be reported as a directory too, but it's still a symlink.
+	switch {
+	case i.metadata.isSymlink():
+		mode |= fs.ModeSymlink
+	case i.metadata.isDir():
+		mode |= fs.ModeDir
+	}
+
+	return mode
+}
+
+func (i *remoteFileInfo) Name() string {
+	return i.name
+}
+
+func (i *remoteFileInfo) Size() int64 {
+	return int64(i.metadata.SizeBytes)
+}
+
+// Sys returns the underlying *FileMetadata.
+func (i *remoteFileInfo) Sys() any {
+	return i.metadata
+}
+
+func (w *remoteFileWriter) Close() error {
+	w.writer.Close()
+
+	return <-w.done
+}
+
+func (w *remoteFileWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+func (s *skipWriter) Write(p []byte) (int, error) {
+	length := len(p)
+
+	if s.skip > 0 {
+		skipped := min(s.skip, int64(len(p)))
+		s.skip -= skipped
+		p = p[skipped:]
+	}
+
+	if len(p) > 0 {
+		if _, err := s.writer.Write(p); err != nil {
+			return 0, err
+		}
+	}
+
+	return length, nil
+}
+
+// mapFileManagerError converts file manager errors into their fs equivalents where possible, so callers can check
+// them with errors.Is, e.g. for fs.ErrNotExist.
+func mapFileManagerError(err error) error {
+	var statusErr *statusCodeError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	switch statusErr.StatusCode {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %w", fs.ErrNotExist, err)
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return fmt.Errorf("%w: %w", fs.ErrPermission, err)
+	}
+
+	return err
+}
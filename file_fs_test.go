@@ -0,0 +1,138 @@
+package directadmin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRemoteFileInfoMode(t *testing.T) {
+	dir := &remoteFileInfo{metadata: &FileMetadata{Type: "dir", UnixMode: 0o755}, name: "public_html"}
+	if mode := dir.Mode(); mode != fs.ModeDir|0o755 || !dir.IsDir() {
+		t.Errorf("expected a 0755 directory, got %v", mode)
+	}
+
+	link := &remoteFileInfo{metadata: &FileMetadata{UnixMode: 0o777, Symlink: struct {
+		Resolved string `json:"resolved"`
+		Target   string `json:"target"`
+	}{Resolved: "/domains/example.com/public_html", Target: "domains/example.com/public_html"}}, name: "www"}
+	if mode := link.Mode(); mode.Type() != fs.ModeSymlink || link.IsDir() {
+		t.Errorf("expected a symlink, got %v", mode)
+	}
+
+	link.metadata.Type = "dir"
+	if mode := link.Mode(); mode.Type() != fs.ModeSymlink || link.IsDir() {
+		t.Errorf("expected a symlink to a directory to be a symlink, got %v", mode)
+	}
+}
+
+func TestFileSystem(t *testing.T) {
+	c, fm := newFakeFileManager(t)
+
+	modified := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fm.addFile("/site/index.html", "<h1>Hello</h1>", modified)
+	fm.addFile("/site/css/style.css", "body { margin: 0; }", modified)
+	fm.addFile("/site/css/empty.css", "", modified)
+	fm.addFile("/site/images/.keep", "", modified)
+	fm.addFile("/outside.txt", "not in the root", modified)
+
+	if err := c.CreateSymlink("index.html", "/site/home.html"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fstest.TestFS(c.FS("site"), "index.html", "css/style.css", "css/empty.css", "images/.keep", "home.html"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Stat(c.FS("site"), "missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	writable := c.WritableFS("site")
+
+	if err := writable.WriteFile("css/new.css", []byte("p {}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if file := fm.files["/site/css/new.css"]; file == nil || string(file.data) != "p {}" || file.mode != 0o600 {
+		t.Errorf("unexpected written file %+v", file)
+	}
+
+	writer, err := writable.Create("streamed.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = io.WriteString(writer, "streamed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = writable.Rename("streamed.txt", "images/streamed.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = writable.RemoveAll("css"); err != nil {
+		t.Fatal(err)
+	}
+
+	if paths := strings.Join(fm.paths(), ","); paths != "/outside.txt,/site,/site/home.html,/site/images,/site/images/.keep,/site/images/streamed.txt,/site/index.html" {
+		t.Errorf("unexpected files after writing: %v", paths)
+	}
+}
+
+func TestRemoteDirReadDir(t *testing.T) {
+	var entries []fs.DirEntry
+	for _, name := range []string{"a", "b", "c"} {
+		entries = append(entries, fs.FileInfoToDirEntry(&remoteFileInfo{metadata: &FileMetadata{Name: name}, name: name}))
+	}
+
+	dir := &remoteDir{entries: entries, listed: true}
+
+	if page, err := dir.ReadDir(2); err != nil || len(page) != 2 || page[1].Name() != "b" {
+		t.Fatalf("unexpected first page %v: %v", page, err)
+	}
+
+	if page, err := dir.ReadDir(2); err != nil || len(page) != 1 || page[0].Name() != "c" {
+		t.Fatalf("unexpected second page %v: %v", page, err)
+	}
+
+	if _, err := dir.ReadDir(2); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestSkipWriter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer := &skipWriter{skip: 5, writer: &buffer}
+	for _, chunk := range []string{"abc", "defg", "hij"} {
+		if n, err := writer.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Fatalf("unexpected write of %q: %d, %v", chunk, n, err)
+		}
+	}
+
+	if buffer.String() != "fghij" {
+		t.Errorf("expected fghij, got %q", buffer.String())
+	}
+}
+
+func TestMapFileManagerError(t *testing.T) {
+	err := mapFileManagerError(fmt.Errorf("error making request: %w", &statusCodeError{StatusCode: 404}))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	if err = mapFileManagerError(nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
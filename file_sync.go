@@ -0,0 +1,491 @@
+package directadmin
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SyncCompareChecksum compares the SHA-256 of files that are the same size, downloading the remote copy.
+	SyncCompareChecksum = SyncCompare("checksum")
+	// SyncCompareSizeAndTime treats files as changed if their sizes differ or the local file was modified after the
+	// remote one. Uploading sets the remote time to the upload time, so a local edit that keeps the file's size but
+	// has an older modification time than the last upload, e.g. after restoring a file with its original time, counts
+	// as unchanged. Use SyncCompareChecksum if that matters.
+	SyncCompareSizeAndTime = SyncCompare("sizeAndTime")
+)
+
+type (
+	SyncCompare string
+
+	// SyncOptions configures SyncDirectory. Zero values upload changed files one at a time, compared by size and
+	// modification time, and leave remote extras in place.
+	SyncOptions struct {
+		// BundleThresholdBytes enables bundling: changed files up to this size are zipped locally, uploaded as a single
+		// archive and extracted server-side, which is much faster for sites with many small files. Bundling only
+		// happens when at least two files qualify.
+		BundleThresholdBytes int64
+		// Compare is how local files are compared with remote ones. Checksum comparisons download each remote file
+		// that's the same size as its local counterpart.
+		Compare SyncCompare
+		// DeleteExtras deletes remote files and directories that don't exist locally, skipping the trash. Excluded
+		// paths are never deleted.
+		DeleteExtras bool
+		// DryRun reports what would change without changing anything.
+		DryRun bool
+		// Exclude skips paths matching any of the patterns, in path.Match syntax. Patterns are matched against both
+		// the slash-separated path relative to the synced directory and the file's base name, e.g. "*.log" or
+		// "node_modules".
+		Exclude []string
+	}
+
+	// SyncReport lists the changes SyncDirectory made, or would make in a dry run, as slash-separated paths relative
+	// to the synced directories.
+	SyncReport struct {
+		// Bundled lists the uploaded files that were sent in an archive.
+		Bundled            []string `json:"bundled"`
+		Deleted            []string `json:"deleted"`
+		DirectoriesCreated []string `json:"directoriesCreated"`
+		DryRun             bool     `json:"dryRun"`
+		Unchanged          int      `json:"unchanged"`
+		Uploaded           []string `json:"uploaded"`
+	}
+
+	localSyncEntry struct {
+		info fs.FileInfo
+		path string
+	}
+)
+
+// SyncDirectory (user) makes the remote directory match the local one, uploading only new and changed files. The report
+// lists what was changed, and is returned along with any error, so it also shows how far a failed sync got.
+func (c *UserContext) SyncDirectory(localDir string, remoteDir string, opts SyncOptions) (*SyncReport, error) {
+	switch opts.Compare {
+	case "":
+		opts.Compare = SyncCompareSizeAndTime
+	case SyncCompareChecksum, SyncCompareSizeAndTime:
+	default:
+		return nil, fmt.Errorf("invalid sync comparison: %v", opts.Compare)
+	}
+
+	for _, pattern := range opts.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	remoteDir = normalizeFilePath(remoteDir)
+	report := &SyncReport{
+		Bundled:            []string{},
+		Deleted:            []string{},
+		DirectoriesCreated: []string{},
+		DryRun:             opts.DryRun,
+		Uploaded:           []string{},
+	}
+
+	localEntries, err := walkLocalSyncDir(localDir, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteEntries, remoteExists, err := c.walkRemoteSyncDir(remoteDir, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		conflicts   []string
+		missingDirs []string
+		toUpload    []string
+	)
+
+	if !remoteExists {
+		missingDirs = append(missingDirs, ".")
+	}
+
+	for _, rel := range slices.Sorted(maps.Keys(localEntries)) {
+		local := localEntries[rel]
+		remote, exists := remoteEntries[rel]
+
+		if exists && remote.isDir() != local.info.IsDir() {
+			// A file is being replaced by a directory or vice versa, so the remote one has to go first.
+			conflicts = append(conflicts, rel)
+			exists = false
+		}
+
+		if local.info.IsDir() {
+			if !exists {
+				missingDirs = append(missingDirs, rel)
+			}
+
+			continue
+		}
+
+		if exists {
+			changed, err := c.syncFileChanged(local, remote, path.Join(remoteDir, rel), opts.Compare)
+			if err != nil {
+				return report, err
+			}
+
+			if !changed {
+				report.Unchanged++
+				continue
+			}
+		}
+
+		toUpload = append(toUpload, rel)
+	}
+
+	var extras []string
+
+	if opts.DeleteExtras {
+		for _, rel := range slices.Sorted(maps.Keys(remoteEntries)) {
+			if _, exists := localEntries[rel]; !exists {
+				extras = append(extras, rel)
+			}
+		}
+	}
+
+	toDelete := topLevelPaths(append(conflicts, extras...))
+	bundled, individual := splitSyncBundle(toUpload, localEntries, opts.BundleThresholdBytes)
+
+	if opts.DryRun {
+		report.Bundled = bundled
+		report.Deleted = toDelete
+		report.DirectoriesCreated = missingDirs
+		report.Uploaded = toUpload
+
+		return report, nil
+	}
+
+	if len(toDelete) > 0 {
+		remotePaths := make([]string, 0, len(toDelete))
+		for _, rel := range toDelete {
+			remotePaths = append(remotePaths, path.Join(remoteDir, rel))
+		}
+
+		if err = c.DeleteFiles(true, remotePaths...); err != nil {
+			return report, fmt.Errorf("failed to delete remote files: %w", err)
+		}
+
+		report.Deleted = toDelete
+	}
+
+	// Creating the deepest directories also creates their parents.
+	for _, rel := range missingDirs {
+		if hasDescendant(rel, missingDirs) {
+			continue
+		}
+
+		if err = c.CreateDirectory(path.Join(remoteDir, rel)); err != nil {
+			return report, fmt.Errorf("failed to create directory %v: %w", rel, err)
+		}
+	}
+
+	report.DirectoriesCreated = missingDirs
+
+	if len(bundled) > 0 {
+		if err = c.uploadSyncBundle(remoteDir, bundled, localEntries); err != nil {
+			return report, err
+		}
+
+		report.Bundled = bundled
+		report.Uploaded = append(report.Uploaded, bundled...)
+	}
+
+	for _, rel := range individual {
+		if err = c.UploadFileFromDisk(path.Join(remoteDir, rel), localEntries[rel].path, true); err != nil {
+			return report, fmt.Errorf("failed to upload %v: %w", rel, err)
+		}
+
+		report.Uploaded = append(report.Uploaded, rel)
+	}
+
+	sort.Strings(report.Uploaded)
+
+	return report, nil
+}
+
+// syncFileChanged returns whether the local file differs from the remote one.
+func (c *UserContext) syncFileChanged(local *localSyncEntry, remote *FileMetadata, remotePath string, compare SyncCompare) (bool, error) {
+	if local.info.Size() != int64(remote.SizeBytes) {
+		return true, nil
+	}
+
+	if compare == SyncCompareSizeAndTime {
+		// Uploading sets the remote modification time to the upload time, so a synced file is never older than its
+		// local copy. DA reports times to the second.
+		return !remote.ModifyTime.IsZero() && local.info.ModTime().Truncate(time.Second).After(remote.ModifyTime), nil
+	}
+
+	localHash := sha256.New()
+
+	file, err := os.Open(local.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(localHash, file); err != nil {
+		return false, fmt.Errorf("failed to checksum %v: %w", local.path, err)
+	}
+
+	remoteHash := sha256.New()
+
+	if _, err = c.DownloadFileTo(remotePath, remoteHash, nil); err != nil {
+		return false, fmt.Errorf("failed to checksum %v: %w", remotePath, err)
+	}
+
+	return !bytes.Equal(localHash.Sum(nil), remoteHash.Sum(nil)), nil
+}
+
+// uploadSyncBundle zips the given files into a temporary archive, uploads it to the remote directory, extracts it over
+// the existing files, and deletes it.
+func (c *UserContext) uploadSyncBundle(remoteDir string, files []string, localEntries map[string]*localSyncEntry) error {
+	archive, err := os.CreateTemp("", "directadmin-sync-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if err = writeSyncBundle(archive, files, localEntries); err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	remoteArchive := path.Join(remoteDir, ".directadmin-sync-"+strconv.FormatInt(time.Now().UnixNano(), 10)+".zip")
+
+	if err = c.UploadFileFrom(remoteArchive, archive, size, true, nil); err != nil {
+		return fmt.Errorf("failed to upload archive: %w", err)
+	}
+
+	extractErr := c.ExtractArchive(remoteDir, remoteArchive, true)
+
+	if err = c.DeleteFiles(true, remoteArchive); err != nil {
+		err = fmt.Errorf("failed to delete archive: %w", err)
+	}
+
+	if extractErr != nil {
+		return errors.Join(fmt.Errorf("failed to extract archive: %w", extractErr), err)
+	}
+
+	return err
+}
+
+// walkRemoteSyncDir returns the remote directory's contents by slash-separated relative path, skipping excluded paths,
+// and whether the directory exists.
+func (c *UserContext) walkRemoteSyncDir(remoteDir string, exclude []string) (map[string]*FileMetadata, bool, error) {
+	entries := make(map[string]*FileMetadata)
+	exists := true
+
+	err := fs.WalkDir(c.FS(remoteDir), ".", func(rel string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if rel == "." && errors.Is(err, fs.ErrNotExist) {
+				exists = false
+				return fs.SkipAll
+			}
+
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		if syncExcluded(rel, exclude) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		entries[rel] = info.Sys().(*FileMetadata)
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list remote directory: %w", err)
+	}
+
+	return entries, exists, nil
+}
+
+// hasDescendant returns whether any of the relative paths is inside dir, where "." contains every other path.
+func hasDescendant(dir string, paths []string) bool {
+	for _, p := range paths {
+		if (dir == "." && p != ".") || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitSyncBundle splits the files to upload into those to bundle into an archive and those to upload individually.
+func splitSyncBundle(files []string, localEntries map[string]*localSyncEntry, thresholdBytes int64) ([]string, []string) {
+	bundled := []string{}
+	individual := []string{}
+
+	for _, rel := range files {
+		if localEntries[rel].info.Size() <= thresholdBytes {
+			bundled = append(bundled, rel)
+		} else {
+			individual = append(individual, rel)
+		}
+	}
+
+	// An archive of a single file is just an extra round trip.
+	if len(bundled) < 2 {
+		return []string{}, files
+	}
+
+	return bundled, individual
+}
+
+// syncExcluded returns whether the relative path or its base name matches any of the patterns, which have already been
+// validated.
+func syncExcluded(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, rel); matched {
+			return true
+		}
+
+		if matched, _ := path.Match(pattern, path.Base(rel)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// topLevelPaths returns the sorted paths, leaving out any inside another of the paths.
+func topLevelPaths(paths []string) []string {
+	sort.Strings(paths)
+
+	topLevel := []string{}
+
+	for _, p := range paths {
+		if len(topLevel) > 0 && strings.HasPrefix(p, topLevel[len(topLevel)-1]+"/") {
+			continue
+		}
+
+		topLevel = append(topLevel, p)
+	}
+
+	return topLevel
+}
+
+// walkLocalSyncDir returns the local directory's regular files and directories by slash-separated relative path,
+// skipping excluded paths.
+func walkLocalSyncDir(localDir string, exclude []string) (map[string]*localSyncEntry, error) {
+	entries := make(map[string]*localSyncEntry)
+
+	err := filepath.WalkDir(localDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(localDir, filePath)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if syncExcluded(rel, exclude) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		// Symlinks and other special files can't be uploaded.
+		if !entry.IsDir() && !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		entries[rel] = &localSyncEntry{info: info, path: filePath}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local directory: %w", err)
+	}
+
+	return entries, nil
+}
+
+// writeSyncBundle writes a zip of the given files to the writer, keeping their relative paths and modification times.
+func writeSyncBundle(writer io.Writer, files []string, localEntries map[string]*localSyncEntry) error {
+	zipWriter := zip.NewWriter(writer)
+
+	for _, rel := range files {
+		local := localEntries[rel]
+
+		header, err := zip.FileInfoHeader(local.info)
+		if err != nil {
+			return err
+		}
+
+		header.Method = zip.Deflate
+		header.Name = rel
+
+		part, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(local.path)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(part, file)
+		file.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
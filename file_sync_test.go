@@ -0,0 +1,231 @@
+package directadmin
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncLocalPlanning(t *testing.T) {
+	localDir := t.TempDir()
+
+	files := map[string]string{
+		"index.html":             "<html></html>",
+		"assets/app.js":          "console.log(1)",
+		"assets/big.bin":         "0123456789abcdef",
+		"logs/access.log":        "GET /",
+		"node_modules/x/main.js": "module.exports = {}",
+		"debug.log":              "trace",
+	}
+
+	for name, contents := range files {
+		filePath := filepath.Join(localDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filePath, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := walkLocalSyncDir(localDir, []string{"*.log", "node_modules"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"assets", "assets/app.js", "assets/big.bin", "index.html", "logs"}
+	if keys := slices.Sorted(maps.Keys(entries)); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+
+	bundled, individual := splitSyncBundle([]string{"assets/app.js", "assets/big.bin", "index.html"}, entries, 15)
+	if !reflect.DeepEqual(bundled, []string{"assets/app.js", "index.html"}) || !reflect.DeepEqual(individual, []string{"assets/big.bin"}) {
+		t.Errorf("unexpected bundle split: %v, %v", bundled, individual)
+	}
+
+	if bundled, _ = splitSyncBundle([]string{"index.html"}, entries, 15); len(bundled) != 0 {
+		t.Errorf("expected a single file not to be bundled, got %v", bundled)
+	}
+
+	var archive bytes.Buffer
+	if err = writeSyncBundle(&archive, []string{"assets/app.js", "index.html"}, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range zipReader.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		contents, err := io.ReadAll(reader)
+		reader.Close()
+
+		if err != nil || string(contents) != files[file.Name] {
+			t.Errorf("unexpected contents for %v: %q, %v", file.Name, contents, err)
+		}
+	}
+
+	deleted := topLevelPaths([]string{"old/a.txt", "old", "stale.txt", "old/sub/b.txt"})
+	if !reflect.DeepEqual(deleted, []string{"old", "stale.txt"}) {
+		t.Errorf("unexpected top-level paths: %v", deleted)
+	}
+}
+
+func TestSyncDirectory(t *testing.T) {
+	lastUpload := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	before := lastUpload.AddDate(0, -1, 0)
+	after := lastUpload.AddDate(0, 1, 0)
+
+	localDir := t.TempDir()
+
+	localFiles := []struct {
+		contents string
+		modified time.Time
+		name     string
+	}{
+		{contents: "console.log(1)", modified: after, name: "assets/app.js"},
+		{contents: "abcd", modified: after, name: "edited.txt"},
+		{contents: "<h1>Hello</h1>", modified: after, name: "index.html"},
+		{contents: "now a file", modified: after, name: "page"},
+		{contents: "same", modified: before, name: "same.txt"},
+		// Same size as the remote copy but older than the last upload, so only a checksum catches it.
+		{contents: "abcd", modified: before, name: "stale.txt"},
+	}
+
+	for _, file := range localFiles {
+		filePath := filepath.Join(localDir, filepath.FromSlash(file.name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filePath, []byte(file.contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(filePath, file.modified, file.modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Mkdir(filepath.Join(localDir, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	newRemote := func() (*UserContext, *fakeFileManager) {
+		c, fm := newFakeFileManager(t)
+
+		// assets is a file remotely but a directory locally, and page is the other way round.
+		fm.addFile("/public_html/assets", "x", lastUpload)
+		fm.addFile("/public_html/edited.txt", "wxyz", lastUpload)
+		fm.addFile("/public_html/extra.txt", "extra", lastUpload)
+		fm.addFile("/public_html/index.html", "old", lastUpload)
+		fm.addFile("/public_html/olddir/x.txt", "x", lastUpload)
+		fm.addFile("/public_html/page/old.txt", "old", lastUpload)
+		fm.addFile("/public_html/same.txt", "same", lastUpload)
+		fm.addFile("/public_html/stale.txt", "wxyz", lastUpload)
+
+		return c, fm
+	}
+
+	expected := &SyncReport{
+		Bundled:            []string{"assets/app.js", "edited.txt", "index.html", "page"},
+		Deleted:            []string{"assets", "extra.txt", "olddir", "page"},
+		DirectoriesCreated: []string{"assets", "empty"},
+		DryRun:             true,
+		Unchanged:          2,
+		Uploaded:           []string{"assets/app.js", "edited.txt", "index.html", "page"},
+	}
+
+	opts := SyncOptions{BundleThresholdBytes: 1024, DeleteExtras: true, DryRun: true}
+
+	c, fm := newRemote()
+	remoteBefore := fm.paths()
+
+	report, err := c.SyncDirectory(localDir, "public_html", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("unexpected dry run report:\nexpected %+v\ngot      %+v", expected, report)
+	}
+
+	if remoteAfter := fm.paths(); !reflect.DeepEqual(remoteAfter, remoteBefore) || fm.requests["filemanager-actions/upload"] != 0 {
+		t.Errorf("expected a dry run not to change anything, got %v", remoteAfter)
+	}
+
+	opts.DryRun = false
+	expected.DryRun = false
+
+	if report, err = c.SyncDirectory(localDir, "public_html", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("unexpected report:\nexpected %+v\ngot      %+v", expected, report)
+	}
+
+	expectedPaths := []string{
+		"/public_html",
+		"/public_html/assets",
+		"/public_html/assets/app.js",
+		"/public_html/edited.txt",
+		"/public_html/empty",
+		"/public_html/index.html",
+		"/public_html/page",
+		"/public_html/same.txt",
+		"/public_html/stale.txt",
+	}
+
+	if paths := fm.paths(); !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("unexpected remote files after sync:\nexpected %v\ngot      %v", expectedPaths, paths)
+	}
+
+	if page := fm.files["/public_html/page"]; page.dir || string(page.data) != "now a file" {
+		t.Errorf("expected page to be replaced by a file, got %+v", page)
+	}
+
+	// Without bundling or deleting extras, files are uploaded one at a time and extras are kept. The checksum catches
+	// the stale file that size and time comparison missed.
+	c, fm = newRemote()
+
+	if report, err = c.SyncDirectory(localDir, "public_html", SyncOptions{Compare: SyncCompareChecksum}); err != nil {
+		t.Fatal(err)
+	}
+
+	if uploaded := strings.Join(report.Uploaded, ","); uploaded != "assets/app.js,edited.txt,index.html,page,stale.txt" || report.Unchanged != 1 || len(report.Bundled) != 0 {
+		t.Errorf("unexpected checksum report %+v", report)
+	}
+
+	if deleted := strings.Join(report.Deleted, ","); deleted != "assets,page" || fm.files["/public_html/extra.txt"] == nil {
+		t.Errorf("expected only conflicting paths to be deleted, got %v", deleted)
+	}
+
+	if fm.requests["filemanager-actions/upload"] != 5 || fm.requests["filemanager-actions/extract-archive"] != 0 {
+		t.Errorf("expected 5 individual uploads, got %v", fm.requests)
+	}
+
+	// A missing remote directory is created, along with everything in it.
+	if report, err = c.SyncDirectory(localDir, "new_site", SyncOptions{DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if created := strings.Join(report.DirectoriesCreated, ","); created != ".,assets,empty" || len(report.Uploaded) != 6 {
+		t.Errorf("unexpected report for a new directory %+v", report)
+	}
+}
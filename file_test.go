@@ -0,0 +1,431 @@
+package directadmin
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	// fakeFileManager is an in-memory stand-in for DA's file manager API, for testing the helpers built on it.
+	fakeFileManager struct {
+		// corruptUploads flips the first byte of every uploaded file, keeping its size.
+		corruptUploads bool
+		// failUploads is the number of uploads to fail before accepting any.
+		failUploads      int
+		files            map[string]*fakeFile
+		maxFilesizeBytes int
+		mu               sync.Mutex
+		// requests counts the requests made to each endpoint, e.g. "filemanager-actions/upload".
+		requests map[string]int
+		// stallUploads holds every upload until the client gives up on it.
+		stallUploads bool
+		// truncateUploads drops the last byte of every uploaded file.
+		truncateUploads bool
+		// uploadLengths are the Content-Length of every upload request, -1 for chunked uploads.
+		uploadLengths []int64
+	}
+
+	fakeFile struct {
+		data     []byte
+		dir      bool
+		modified time.Time
+		mode     int
+		// target is set for symlinks.
+		target string
+	}
+)
+
+// newFakeFileManager starts a fake file manager server containing only the home directory, and returns a user
+// context logged into it.
+func newFakeFileManager(t *testing.T) (*UserContext, *fakeFileManager) {
+	t.Helper()
+
+	fm := &fakeFileManager{
+		files:    map[string]*fakeFile{"/": {dir: true, mode: 0o755}},
+		requests: make(map[string]int),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(fm.serveHTTP))
+	t.Cleanup(server.Close)
+
+	api, err := New(server.URL, time.Minute, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &UserContext{api: api, cookieJar: jar, credentials: credentials{username: "user", passkey: "passkey"}}, fm
+}
+
+// addFile adds a file, creating any missing parent directories.
+func (fm *fakeFileManager) addFile(filePath string, data string, modified time.Time) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	fm.mkdirAll(path.Dir(filePath))
+	fm.files[filePath] = &fakeFile{data: []byte(data), modified: modified, mode: 0o644}
+}
+
+// paths returns every path in the fake file manager except the home directory, sorted.
+func (fm *fakeFileManager) paths() []string {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	paths := make([]string, 0, len(fm.files))
+	for filePath := range fm.files {
+		if filePath != "/" {
+			paths = append(paths, filePath)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+func (fm *fakeFileManager) metadata(filePath string, file *fakeFile) *FileMetadata {
+	metadata := &FileMetadata{
+		ModifyTime: file.modified,
+		Name:       path.Base(filePath),
+		SizeBytes:  len(file.data),
+		Type:       "file",
+		UnixMode:   file.mode,
+	}
+
+	switch {
+	case file.target != "":
+		metadata.Type = "link"
+		metadata.Symlink.Target = file.target
+		metadata.Symlink.Resolved = path.Join(path.Dir(filePath), file.target)
+	case file.dir:
+		metadata.Type = "dir"
+	}
+
+	return metadata
+}
+
+func (fm *fakeFileManager) mkdirAll(dirPath string) {
+	for ; dirPath != "/"; dirPath = path.Dir(dirPath) {
+		if _, exists := fm.files[dirPath]; !exists {
+			fm.files[dirPath] = &fakeFile{dir: true, mode: 0o755}
+		}
+	}
+}
+
+func (fm *fakeFileManager) removeAll(filePath string) {
+	for existing := range fm.files {
+		if existing == filePath || strings.HasPrefix(existing, filePath+"/") {
+			delete(fm.files, existing)
+		}
+	}
+}
+
+func (fm *fakeFileManager) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	endpoint := strings.TrimPrefix(r.URL.Path, "/api/")
+	fm.requests[endpoint]++
+
+	var body struct {
+		Destination       string   `json:"destination"`
+		DestinationDir    string   `json:"destinationDir"`
+		MergeAndOverwrite bool     `json:"mergeAndOverwrite"`
+		Mode              string   `json:"mode"`
+		Overwrite         bool     `json:"overwrite"`
+		Path              string   `json:"path"`
+		Paths             []string `json:"paths"`
+		Source            string   `json:"source"`
+		Target            string   `json:"target"`
+	}
+
+	if r.Method == http.MethodPost && endpoint != "filemanager-actions/upload" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	file := fm.files[query.Get("path")]
+
+	switch endpoint {
+	case "session":
+		var session Session
+		session.DirectadminConfig.MaxFilesizeBytes = fm.maxFilesizeBytes
+		json.NewEncoder(w).Encode(session)
+	case "filemanager/download":
+		if file == nil || file.dir {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write(file.data)
+	case "filemanager/list":
+		if file == nil || !file.dir {
+			http.NotFound(w, r)
+			return
+		}
+
+		entries := []*FileMetadata{}
+		for filePath, child := range fm.files {
+			if filePath != "/" && path.Dir(filePath) == query.Get("path") {
+				entries = append(entries, fm.metadata(filePath, child))
+			}
+		}
+
+		json.NewEncoder(w).Encode(entries)
+	case "filemanager/metadata":
+		if file == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		json.NewEncoder(w).Encode(fm.metadata(query.Get("path"), file))
+	case "filemanager-actions/chmod":
+		mode, err := strconv.ParseInt(body.Mode, 8, 32)
+		if err != nil || fm.files[body.Path] == nil {
+			http.Error(w, "invalid chmod", http.StatusBadRequest)
+			return
+		}
+
+		fm.files[body.Path].mode = int(mode)
+	case "filemanager-actions/extract-archive":
+		archive := fm.files[body.Source]
+		if archive == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		zipReader, err := zip.NewReader(bytes.NewReader(archive.data), int64(len(archive.data)))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for _, zipFile := range zipReader.File {
+			reader, err := zipFile.Open()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			data, _ := io.ReadAll(reader)
+			reader.Close()
+
+			filePath := path.Join(body.DestinationDir, zipFile.Name)
+			fm.mkdirAll(path.Dir(filePath))
+			fm.files[filePath] = &fakeFile{data: data, modified: time.Now(), mode: 0o644}
+		}
+	case "filemanager-actions/mkdir":
+		fm.mkdirAll(body.Path)
+	case "filemanager-actions/move":
+		source := fm.files[body.Source]
+		if source == nil || (fm.files[body.Destination] != nil && !body.Overwrite) {
+			http.Error(w, "can't move", http.StatusBadRequest)
+			return
+		}
+
+		fm.files[body.Destination] = source
+		delete(fm.files, body.Source)
+	case "filemanager-actions/remove":
+		for _, filePath := range body.Paths {
+			fm.removeAll(filePath)
+		}
+	case "filemanager-actions/symlink":
+		fm.files[body.Path] = &fakeFile{mode: 0o777, modified: time.Now(), target: body.Target}
+	case "filemanager-actions/upload":
+		fm.uploadLengths = append(fm.uploadLengths, r.ContentLength)
+
+		if fm.stallUploads {
+			fm.mu.Unlock()
+			// The server only notices the client going away once the body has been read.
+			_, _ = io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+			fm.mu.Lock()
+
+			return
+		}
+
+		uploaded, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		data, _ := io.ReadAll(uploaded)
+
+		if fm.failUploads > 0 {
+			fm.failUploads--
+			http.Error(w, "upload failed", http.StatusInternalServerError)
+
+			return
+		}
+
+		filePath := path.Join(query.Get("dir"), query.Get("name"))
+
+		if parent := fm.files[path.Dir(filePath)]; parent == nil || !parent.dir {
+			http.NotFound(w, r)
+			return
+		}
+
+		if fm.files[filePath] != nil && query.Get("overwrite") != "true" {
+			http.Error(w, "file exists", http.StatusConflict)
+			return
+		}
+
+		if fm.corruptUploads && len(data) > 0 {
+			data[0] ^= 0xff
+		}
+
+		if fm.truncateUploads && len(data) > 0 {
+			data = data[:len(data)-1]
+		}
+
+		fm.files[filePath] = &fakeFile{data: data, modified: time.Now(), mode: 0o644}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestNormalizeFilePath(t *testing.T) {
+	tests := map[string]string{
+		"":                                      "/",
+		"domains/example.com/public_html/admin": "/domains/example.com/public_html/admin",
+		"/domains/example.com/public_html/admin/": "/domains/example.com/public_html/admin",
+		"domains//example.com/./private/":         "/domains/example.com/private",
+		"../../etc/passwd":                        "/etc/passwd",
+	}
+
+	for input, expected := range tests {
+		if normalized := normalizeFilePath(input); normalized != expected {
+			t.Errorf("%q: expected %q, got %q", input, expected, normalized)
+		}
+	}
+}
+
+func TestDestructiveActionsRefuseHomeDirectory(t *testing.T) {
+	c, fm := newFakeFileManager(t)
+	fm.addFile("/file.txt", "data", time.Now())
+
+	for _, filePath := range []string{"", ".", "/", "..", "../..", "domains/.."} {
+		if err := c.DeleteFiles(true, "file.txt", filePath); err == nil {
+			t.Errorf("%q: expected DeleteFiles to refuse the home directory", filePath)
+		}
+
+		if err := c.SetPermissions(filePath, 0o755, true); err == nil {
+			t.Errorf("%q: expected a recursive SetPermissions to refuse the home directory", filePath)
+		}
+
+		if err := c.UnprotectDirectory(filePath); err == nil {
+			t.Errorf("%q: expected UnprotectDirectory to refuse the home directory", filePath)
+		}
+
+		if err := c.MovePath(filePath, "moved", false); err == nil {
+			t.Errorf("%q: expected MovePath to refuse to move the home directory", filePath)
+		}
+
+		if err := c.MovePath("file.txt", filePath, true); err == nil {
+			t.Errorf("%q: expected MovePath to refuse to replace the home directory", filePath)
+		}
+	}
+
+	if fm.requests["filemanager-actions/remove"] != 0 || fm.requests["filemanager-actions/chmod"] != 0 || fm.requests["filemanager-actions/move"] != 0 || fm.files["/file.txt"] == nil {
+		t.Errorf("expected no requests to be sent, got %v", fm.requests)
+	}
+}
+
+func TestUploadFileContentLength(t *testing.T) {
+	c, fm := newFakeFileManager(t)
+
+	if err := c.UploadFile("known.txt", []byte("data"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.UploadFileFrom("unknown.txt", strings.NewReader("data"), -1, false, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fm.uploadLengths) != 2 || fm.uploadLengths[0] <= int64(len("data")) || fm.uploadLengths[1] != -1 {
+		t.Errorf("expected only the upload of a known size to send a content length, got %v", fm.uploadLengths)
+	}
+
+	if data, err := c.DownloadFile("../known.txt"); err != nil || string(data) != "data" {
+		t.Errorf("expected the normalised path to be downloaded, got %q, %v", data, err)
+	}
+}
+
+func TestCreateDirectory(t *testing.T) {
+	c, fm := newFakeFileManager(t)
+
+	if err := c.CreateDirectory("domains/example.com/private/"); err != nil {
+		t.Fatal(err)
+	}
+
+	if paths := strings.Join(fm.paths(), ","); paths != "/domains,/domains/example.com,/domains/example.com/private" {
+		t.Errorf("expected the normalised directory and its parents, got %v", paths)
+	}
+}
+
+func TestSortFileMetadata(t *testing.T) {
+	newEntries := func() []*FileMetadata {
+		return []*FileMetadata{
+			{Name: "b.txt", SizeBytes: 10, Type: "file", ModifyTime: time.Unix(300, 0)},
+			{Name: "public_html", SizeBytes: 4096, Type: "dir", ModifyTime: time.Unix(100, 0)},
+			{Name: "a.txt", SizeBytes: 10, Type: "file", ModifyTime: time.Unix(200, 0)},
+		}
+	}
+
+	tests := []struct {
+		expected []string
+		opts     ListDirectoryOptions
+	}{
+		{expected: []string{"a.txt", "b.txt", "public_html"}, opts: ListDirectoryOptions{SortBy: FileSortName}},
+		{expected: []string{"public_html", "a.txt", "b.txt"}, opts: ListDirectoryOptions{DirectoriesFirst: true, SortBy: FileSortName}},
+		{expected: []string{"public_html", "b.txt", "a.txt"}, opts: ListDirectoryOptions{SortBy: FileSortSize, SortDescending: true}},
+		{expected: []string{"public_html", "a.txt", "b.txt"}, opts: ListDirectoryOptions{SortBy: FileSortModified}},
+	}
+
+	for _, test := range tests {
+		entries := newEntries()
+		sortFileMetadata(entries, test.opts)
+
+		for i, name := range test.expected {
+			if entries[i].Name != name {
+				t.Errorf("%+v: expected %v at %d, got %v", test.opts, name, i, entries[i].Name)
+			}
+		}
+	}
+}
+
+func TestUnixModeConversion(t *testing.T) {
+	for _, unixMode := range []int{0o644, 0o755, 0o4755, 0o2770, 0o1777} {
+		mode := unixModeToFileMode(unixMode)
+
+		if formatted := formatUnixMode(mode); formatted != fmt.Sprintf("%04o", unixMode) {
+			t.Errorf("expected %04o, got %v", unixMode, formatted)
+		}
+	}
+
+	if mode := unixModeToFileMode(0o4755); mode&fs.ModeSetuid == 0 {
+		t.Errorf("expected setuid in %v", mode)
+	}
+}
@@ -0,0 +1,180 @@
+package directadmin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultUploadRetries    = 3
+	defaultUploadRetryDelay = 2 * time.Second
+)
+
+// UploadRetryOptions configures UploadFileWithRetries. Zero values use the defaults.
+type UploadRetryOptions struct {
+	// MaxRetries is how many times a failed upload is retried before giving up. Zero uses the default of 3, and a
+	// negative value disables retries.
+	MaxRetries int
+	Overwrite  bool
+	// Progress is called with the bytes uploaded by the current attempt.
+	Progress ProgressFunc
+	// RetryDelay is the wait before the first retry, doubling after each attempt. Defaults to 2 seconds.
+	RetryDelay time.Duration
+	// SkipChecksum skips downloading the uploaded file to compare its SHA-256 with the local file's. The size is always
+	// verified.
+	SkipChecksum bool
+}
+
+// UploadFileWithRetries (user) uploads size bytes from the reader to the given path, retrying failed attempts until
+// ctx is done, and verifies the upload's size and checksum before moving it into place. Files over the panel's max
+// upload size are refused.
+//
+// The file is staged in a hidden file next to the destination, named after its checksum, so the destination is never
+// left half-written. A complete staged file left by an earlier call is verified and reused rather than uploaded again.
+//
+// Uploads can't be split into chunks and resumed part way through, as DA's file manager has no way to join files
+// server-side, so every attempt sends the whole file.
+func (c *UserContext) UploadFileWithRetries(ctx context.Context, uploadToPath string, reader io.ReaderAt, size int64, opts UploadRetryOptions) error {
+	uploadToPath = normalizeFilePath(uploadToPath)
+
+	session, err := c.GetSessionInfo()
+	if err != nil {
+		return fmt.Errorf("failed to get max upload size: %w", err)
+	}
+
+	if maxFilesize := int64(session.DirectadminConfig.MaxFilesizeBytes); maxFilesize > 0 && size > maxFilesize {
+		return fmt.Errorf("file is %d bytes, which exceeds the max upload size of %d bytes", size, maxFilesize)
+	}
+
+	// Moving the staged file into place would fail anyway, so don't waste an upload.
+	if !opts.Overwrite {
+		if _, err = c.GetFileMetadata(uploadToPath); err == nil {
+			return fmt.Errorf("%v already exists", uploadToPath)
+		}
+	}
+
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultUploadRetries
+	}
+
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultUploadRetryDelay
+	}
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, io.NewSectionReader(reader, 0, size)); err != nil {
+		return fmt.Errorf("failed to checksum file: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	stagingPath := path.Join(path.Dir(uploadToPath), "."+path.Base(uploadToPath)+"."+checksum[:16]+".part")
+
+	if metadata, metadataErr := c.GetFileMetadata(stagingPath); metadataErr == nil && int64(metadata.SizeBytes) == size {
+		if opts.Progress != nil {
+			opts.Progress(size, size)
+		}
+	} else if err = c.uploadWithRetries(ctx, stagingPath, io.NewSectionReader(reader, 0, size), opts); err != nil {
+		return err
+	}
+
+	if err = c.verifyUploadedFile(stagingPath, size, checksum, !opts.SkipChecksum); err != nil {
+		// The staged file can't be trusted, so make sure the next call starts from scratch.
+		if deleteErr := c.DeleteFiles(true, stagingPath); deleteErr != nil {
+			return fmt.Errorf("%w: %w", err, deleteErr)
+		}
+
+		return err
+	}
+
+	if err = c.MovePath(stagingPath, uploadToPath, opts.Overwrite); err != nil {
+		return fmt.Errorf("failed to move uploaded file into place: %w", err)
+	}
+
+	return nil
+}
+
+// UploadFileFromDiskWithRetries (user) wraps UploadFileWithRetries for a local file.
+func (c *UserContext) UploadFileFromDiskWithRetries(ctx context.Context, uploadToPath string, localFilePath string, opts UploadRetryOptions) error {
+	file, err := os.Open(filepath.Clean(localFilePath))
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return c.UploadFileWithRetries(ctx, uploadToPath, file, fileInfo.Size(), opts)
+}
+
+// uploadWithRetries uploads the file to the given path, retrying failed uploads with an increasing delay until the
+// retries run out or ctx is done. An upload in progress is aborted when ctx is done.
+func (c *UserContext) uploadWithRetries(ctx context.Context, uploadToPath string, file *io.SectionReader, opts UploadRetryOptions) error {
+	delay := opts.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		err := c.uploadFile(ctx, uploadToPath, file, file.Size(), true, opts.Progress)
+		if err == nil {
+			return nil
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("%w, last attempt failed: %w", ctxErr, err)
+		}
+
+		if attempt >= opts.MaxRetries {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w, last attempt failed: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		delay *= 2
+	}
+}
+
+// verifyUploadedFile checks the uploaded file's size, and its checksum if requested.
+func (c *UserContext) verifyUploadedFile(filePath string, size int64, checksum string, verifyChecksum bool) error {
+	metadata, err := c.GetFileMetadata(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to verify uploaded file: %w", err)
+	}
+
+	if int64(metadata.SizeBytes) != size {
+		return fmt.Errorf("uploaded file is %d bytes, expected %d", metadata.SizeBytes, size)
+	}
+
+	if !verifyChecksum {
+		return nil
+	}
+
+	hash := sha256.New()
+	if _, err = c.DownloadFileTo(filePath, hash, nil); err != nil {
+		return fmt.Errorf("failed to verify uploaded file: %w", err)
+	}
+
+	if uploadedChecksum := hex.EncodeToString(hash.Sum(nil)); uploadedChecksum != checksum {
+		return fmt.Errorf("uploaded file checksum %v doesn't match local checksum %v", uploadedChecksum, checksum)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package directadmin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadFileWithRetries(t *testing.T) {
+	const fileData = "backup archive contents"
+
+	checksum := sha256.Sum256([]byte(fileData))
+	stagingPath := "/backups/.site.tar.gz." + hex.EncodeToString(checksum[:])[:16] + ".part"
+	opts := UploadRetryOptions{RetryDelay: time.Millisecond}
+
+	tests := []struct {
+		expectedErr     string
+		expectedUploads int
+		name            string
+		opts            UploadRetryOptions
+		setup           func(fm *fakeFileManager)
+	}{
+		{
+			expectedUploads: 1,
+			name:            "upload",
+			opts:            opts,
+		},
+		{
+			expectedUploads: 0,
+			name:            "reuse a complete staged file",
+			opts:            opts,
+			setup: func(fm *fakeFileManager) {
+				fm.addFile(stagingPath, fileData, time.Now())
+			},
+		},
+		{
+			expectedUploads: 3,
+			name:            "retry",
+			opts:            opts,
+			setup: func(fm *fakeFileManager) {
+				fm.failUploads = 2
+			},
+		},
+		{
+			expectedErr:     "giving up after 4 attempts",
+			expectedUploads: 4,
+			name:            "give up after the default retries",
+			opts:            opts,
+			setup: func(fm *fakeFileManager) {
+				fm.failUploads = 10
+			},
+		},
+		{
+			expectedErr:     "giving up after 1 attempts",
+			expectedUploads: 1,
+			name:            "no retries",
+			opts:            UploadRetryOptions{MaxRetries: -1},
+			setup: func(fm *fakeFileManager) {
+				fm.failUploads = 1
+			},
+		},
+		{
+			expectedErr:     "uploaded file is 22 bytes, expected 23",
+			expectedUploads: 1,
+			name:            "size mismatch",
+			opts:            UploadRetryOptions{SkipChecksum: true},
+			setup: func(fm *fakeFileManager) {
+				fm.truncateUploads = true
+			},
+		},
+		{
+			expectedErr:     "doesn't match local checksum",
+			expectedUploads: 1,
+			name:            "checksum mismatch",
+			opts:            opts,
+			setup: func(fm *fakeFileManager) {
+				fm.corruptUploads = true
+			},
+		},
+		{
+			expectedErr: "exceeds the max upload size",
+			name:        "too large",
+			opts:        opts,
+			setup: func(fm *fakeFileManager) {
+				fm.maxFilesizeBytes = 10
+			},
+		},
+		{
+			expectedErr: "already exists",
+			name:        "no overwrite",
+			opts:        opts,
+			setup: func(fm *fakeFileManager) {
+				fm.addFile("/backups/site.tar.gz", "old", time.Now())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		c, fm := newFakeFileManager(t)
+		fm.addFile("/backups/.keep", "", time.Now())
+
+		if test.setup != nil {
+			test.setup(fm)
+		}
+
+		err := c.UploadFileWithRetries(context.Background(), "backups/site.tar.gz", strings.NewReader(fileData), int64(len(fileData)), test.opts)
+
+		if uploads := fm.requests["filemanager-actions/upload"]; uploads != test.expectedUploads {
+			t.Errorf("%v: expected %d uploads, got %d", test.name, test.expectedUploads, uploads)
+		}
+
+		if test.expectedErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+				t.Errorf("%v: expected an error containing %q, got %v", test.name, test.expectedErr, err)
+			}
+
+			// A staged file that failed verification must not be reused.
+			if _, exists := fm.files[stagingPath]; exists && strings.Contains(test.name, "mismatch") {
+				t.Errorf("%v: expected the staged file to be removed", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		if uploaded := fm.files["/backups/site.tar.gz"]; uploaded == nil || string(uploaded.data) != fileData {
+			t.Errorf("%v: expected the file to be uploaded, got %+v", test.name, uploaded)
+		}
+
+		if _, exists := fm.files[stagingPath]; exists {
+			t.Errorf("%v: expected the staged file to be moved into place", test.name)
+		}
+	}
+}
+
+func TestUploadFileWithRetriesCancel(t *testing.T) {
+	c, fm := newFakeFileManager(t)
+	fm.failUploads = 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := c.UploadFileWithRetries(ctx, "file.txt", strings.NewReader("data"), 4, UploadRetryOptions{RetryDelay: time.Hour})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the retry wait to stop when the context is done, got %v", err)
+	}
+
+	// An upload in progress is aborted too, rather than only being checked between attempts.
+	c, fm = newFakeFileManager(t)
+	fm.stallUploads = true
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = c.UploadFileWithRetries(ctx, "file.txt", strings.NewReader("data"), 4, UploadRetryOptions{RetryDelay: time.Hour})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the upload to be aborted when the context is done, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package directadmin
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +18,11 @@ const debugBodyLimit = 32768
 
 const sessionTimeout = 1 * time.Hour
 
+// statusCodeError is returned when DA responds with a non-2xx status code.
+type statusCodeError struct {
+	StatusCode int
+}
+
 type httpDebug struct {
 	Body          string
 	BodyTruncated bool
@@ -37,8 +43,81 @@ func (c *UserContext) getRequestURLOld(endpoint string) string {
 
 // makeRequest is the underlying function for HTTP requests. It handles debugging statements, and simple error handling.
 func (c *UserContext) makeRequest(req *http.Request) ([]byte, error) {
-	var debugCookies []string
+	debug := httpDebug{
+		Endpoint: getPathWithQuery(req),
+		Method:   req.Method,
+		Start:    time.Now(),
+	}
+	defer c.api.printDebugHTTP(&debug)
+
+	resp, err := c.sendRequest(req, &debug)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var responseBytes []byte
+
+	if resp.Body != nil {
+		responseBytes, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body: %w", err)
+		}
+
+		if c.api.debug {
+			if len(responseBytes) > debugBodyLimit {
+				debug.BodyTruncated = true
+				debug.Body = string(responseBytes[:debugBodyLimit])
+			} else {
+				debug.Body = string(responseBytes)
+			}
+		}
+	}
 
+	if resp.StatusCode/100 != 2 {
+		return responseBytes, &statusCodeError{StatusCode: resp.StatusCode}
+	}
+
+	return responseBytes, nil
+}
+
+// makeRequestStream is like makeRequest, but copies a successful response body to the given writer instead of reading
+// it into memory, reporting progress if a progress function is given. It returns the number of bytes written.
+func (c *UserContext) makeRequestStream(req *http.Request, writer io.Writer, progress ProgressFunc) (int64, error) {
+	debug := httpDebug{
+		Endpoint: getPathWithQuery(req),
+		Method:   req.Method,
+		Start:    time.Now(),
+	}
+	defer c.api.printDebugHTTP(&debug)
+
+	resp, err := c.sendRequest(req, &debug)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		// Error responses are small, so include the start of the body to help with debugging.
+		responseBytes, _ := io.ReadAll(io.LimitReader(resp.Body, debugBodyLimit))
+		debug.Body = string(responseBytes)
+
+		return 0, &statusCodeError{StatusCode: resp.StatusCode}
+	}
+
+	debug.Body = "(streamed)"
+
+	written, err := io.Copy(withProgressWriter(writer, resp.ContentLength, progress), resp.Body)
+	if err != nil {
+		return written, fmt.Errorf("error streaming response body: %w", err)
+	}
+
+	return written, nil
+}
+
+// sendRequest adds the session's cookies or credentials to the request, sends it, and stores any cookies in the
+// response. The caller must close the response body.
+func (c *UserContext) sendRequest(req *http.Request, debug *httpDebug) (*http.Response, error) {
 	cookiesToSet := c.cookieJar.Cookies(req.URL)
 	sessionCookieSet := false
 	for _, cookie := range cookiesToSet {
@@ -51,18 +130,10 @@ func (c *UserContext) makeRequest(req *http.Request) ([]byte, error) {
 		}
 
 		if c.api.debug {
-			debugCookies = append(debugCookies, cookie.String())
+			debug.Cookies = append(debug.Cookies, cookie.String())
 		}
 	}
 
-	debug := httpDebug{
-		Cookies:  debugCookies,
-		Endpoint: getPathWithQuery(req),
-		Method:   req.Method,
-		Start:    time.Now(),
-	}
-	defer c.api.printDebugHTTP(&debug)
-
 	if !sessionCookieSet {
 		req.SetBasicAuth(c.credentials.username, c.credentials.passkey)
 	}
@@ -71,7 +142,6 @@ func (c *UserContext) makeRequest(req *http.Request) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	if c.api.debug {
 		debug.Code = resp.StatusCode
@@ -90,29 +160,7 @@ func (c *UserContext) makeRequest(req *http.Request) ([]byte, error) {
 		c.cookieJar.SetCookies(req.URL, []*http.Cookie{cookie})
 	}
 
-	var responseBytes []byte
-
-	if resp.Body != nil {
-		responseBytes, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("error reading response body: %w", err)
-		}
-
-		if c.api.debug {
-			if len(responseBytes) > debugBodyLimit {
-				debug.BodyTruncated = true
-				debug.Body = string(responseBytes[:debugBodyLimit])
-			} else {
-				debug.Body = string(responseBytes)
-			}
-		}
-	}
-
-	if resp.StatusCode/100 != 2 {
-		return responseBytes, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	return responseBytes, nil
+	return resp, nil
 }
 
 // makeRequestNew supports DirectAdmin's new API.
@@ -154,6 +202,47 @@ func (c *UserContext) makeRequestNew(method string, endpoint string, body any, o
 	return resp, nil
 }
 
+// makeRequestNewStream supports downloads from DirectAdmin's new API, copying the response body to the given writer
+// as it's received.
+func (c *UserContext) makeRequestNewStream(endpoint string, writer io.Writer, progress ProgressFunc) (int64, error) {
+	req, err := http.NewRequest(http.MethodGet, c.getRequestURLNew(endpoint), nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Referer", c.api.url)
+	req.Header.Set("User-Agent", "DirectAdmin-Go-SDK")
+
+	written, err := c.makeRequestStream(req, writer, progress)
+	if err != nil {
+		return written, fmt.Errorf("error making request: %w", err)
+	}
+
+	return written, nil
+}
+
+// makeRequestOldStream supports downloads from DirectAdmin's old API, copying the response body to the given writer
+// as it's received.
+func (c *UserContext) makeRequestOldStream(method string, endpoint string, body url.Values, writer io.Writer, progress ProgressFunc) (int64, error) {
+	req, err := http.NewRequest(strings.ToUpper(method), c.getRequestURLOld(endpoint), strings.NewReader(body.Encode()))
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	query := req.URL.Query()
+	query.Add("json", "yes")
+	req.Header.Set("Referer", c.api.url)
+	req.Header.Set("User-Agent", "DirectAdmin-Go-SDK")
+	req.URL.RawQuery = query.Encode()
+
+	written, err := c.makeRequestStream(req, writer, progress)
+	if err != nil {
+		return written, fmt.Errorf("error making request: %w", err)
+	}
+
+	return written, nil
+}
+
 // makeRequestOld supports DirectAdmin's old API.
 func (c *UserContext) makeRequestOld(method string, endpoint string, body url.Values, object any) ([]byte, error) {
 	req, err := http.NewRequest(strings.ToUpper(method), c.getRequestURLOld(endpoint), strings.NewReader(body.Encode()))
@@ -194,13 +283,24 @@ func (c *UserContext) makeRequestOld(method string, endpoint string, body url.Va
 	return resp, nil
 }
 
-// uploadFile functions for either the old or new DA API.
-func (c *UserContext) uploadFile(method string, endpoint string, data []byte, object any, contentType string) ([]byte, error) {
-	req, err := http.NewRequest(strings.ToUpper(method), c.api.url+endpoint, bytes.NewBuffer(data))
+// uploadStream uploads to either the old or new DA API, sending the body from the given reader as it's read, and
+// aborting the upload if ctx is done. If the reader is an io.Closer, it's closed once the request has finished. The content length is sent if it isn't -1,
+// otherwise the body is sent chunked.
+func (c *UserContext) uploadStream(ctx context.Context, method string, endpoint string, body io.Reader, contentLength int64, object any, contentType string) ([]byte, error) {
+	if closer, ok := body.(io.Closer); ok {
+		// Closing the body unblocks anything still writing to it, e.g. a pipe, if the request ended early.
+		defer closer.Close()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), c.api.url+endpoint, body)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
+	if contentLength != -1 {
+		req.ContentLength = contentLength
+	}
+
 	query := req.URL.Query()
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", contentType)
@@ -221,6 +321,10 @@ func (c *UserContext) uploadFile(method string, endpoint string, data []byte, ob
 	return resp, nil
 }
 
+func (e *statusCodeError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
 func (a *API) printDebugHTTP(debug *httpDebug) {
 	if a.debug {
 		var bodyTruncated string
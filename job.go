@@ -0,0 +1,329 @@
+package directadmin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// jobPollInterval is how often Wait checks a job's state.
+const jobPollInterval = 10 * time.Second
+
+// jobMessageSkew allows for clock differences between the client and the server when matching messages to jobs.
+const jobMessageSkew = time.Minute
+
+const (
+	JobKindBackup  = JobKind("backup")
+	JobKindRestore = JobKind("restore")
+	JobKindSSL     = JobKind("ssl")
+
+	JobStatusFailed    = JobStatus("failed")
+	JobStatusRunning   = JobStatus("running")
+	JobStatusSucceeded = JobStatus("succeeded")
+)
+
+type (
+	JobKind   string
+	JobStatus string
+
+	// Job is a handle to an operation DA has queued to run in the background. A Job isn't safe for concurrent use.
+	Job struct {
+		Domain  string    `json:"domain"`
+		Kind    JobKind   `json:"kind"`
+		Started time.Time `json:"started"`
+
+		// check returns the job's result, or nil if it's still running.
+		check  func() (*JobResult, error)
+		result *JobResult
+	}
+
+	// JobFailedError is returned by Wait when DA reports that the job failed.
+	JobFailedError struct {
+		Domain  string
+		Kind    JobKind
+		Message string
+	}
+
+	JobResult struct {
+		Finished time.Time `json:"finished"`
+		Message  string    `json:"message"`
+		// Output is the job's product, if any, e.g. the backup filename.
+		Output string    `json:"output"`
+		Status JobStatus `json:"status"`
+	}
+)
+
+func (e *JobFailedError) Error() string {
+	return fmt.Sprintf("%v job for %v failed: %v", e.Kind, e.Domain, e.Message)
+}
+
+// Poll checks the job's state once. Once the job has finished, the same result is returned without checking again.
+func (j *Job) Poll() (*JobResult, error) {
+	if j.result != nil {
+		return j.result, nil
+	}
+
+	result, err := j.check()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %v job: %w", j.Kind, err)
+	}
+
+	if result == nil {
+		return &JobResult{Status: JobStatusRunning}, nil
+	}
+
+	if result.Finished.IsZero() {
+		result.Finished = time.Now()
+	}
+
+	j.result = result
+
+	return result, nil
+}
+
+// Wait polls the job until it has finished, or until ctx is done. If the job failed, the result is returned along
+// with a *JobFailedError.
+func (j *Job) Wait(ctx context.Context) (*JobResult, error) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := j.Poll()
+		if err != nil {
+			return nil, err
+		}
+
+		switch result.Status {
+		case JobStatusFailed:
+			return result, &JobFailedError{Domain: j.Domain, Kind: j.Kind, Message: result.Message}
+		case JobStatusSucceeded:
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func newJob(kind JobKind, domain string, check func() (*JobResult, error)) *Job {
+	return &Job{
+		Domain:  domain,
+		Kind:    kind,
+		Started: time.Now(),
+		check:   check,
+	}
+}
+
+// findJobMessage returns the newest message received since the given time whose subject mentions any of the given
+// topics, and whose subject or body mentions the domain, or nil if there isn't one. Notifications that don't name the
+// domain are ignored, as they may be about another domain's job.
+func findJobMessage(messages []*Message, since time.Time, domain string, topics ...string) *Message {
+	var found *Message
+
+	since = since.Add(-jobMessageSkew)
+
+	for _, message := range messages {
+		if message.Timestamp.Before(since) || (found != nil && message.Timestamp.Before(found.Timestamp)) {
+			continue
+		}
+
+		if !mentionsDomain(message.Subject, domain) && !mentionsDomain(message.Message, domain) {
+			continue
+		}
+
+		subject := strings.ToLower(message.Subject)
+
+		for _, topic := range topics {
+			if strings.Contains(subject, topic) {
+				found = message
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// mentionsDomain reports whether the text contains the domain as a whole name, so example.com doesn't match
+// www.example.com or example.com.au.
+func mentionsDomain(text string, domain string) bool {
+	text = strings.ToLower(text)
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if domain == "" {
+		return false
+	}
+
+	isNameChar := func(b byte) bool {
+		return b == '-' || b == '.' || b == '_' || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+	}
+
+	for offset := 0; ; {
+		index := strings.Index(text[offset:], domain)
+		if index == -1 {
+			return false
+		}
+
+		start := offset + index
+		end := start + len(domain)
+
+		// A trailing dot ending a sentence is fine, one followed by another label isn't.
+		before := start == 0 || !isNameChar(text[start-1])
+		after := end == len(text) || !isNameChar(text[end]) || (text[end] == '.' && (end+1 == len(text) || !isNameChar(text[end+1])))
+
+		if before && after {
+			return true
+		}
+
+		offset = start + 1
+	}
+}
+
+// jobMessageResult converts DA's notification about a job into a result, or returns nil if the notification doesn't
+// clearly report whether the job succeeded. DA's messages carry no structured status, so the subject's words are
+// matched against known failure and success words. Only whole words count, so a domain such as failover.tld or a body
+// reporting "0 errors" doesn't fail the job, and failure words win over success words.
+func jobMessageResult(message *Message) *JobResult {
+	var failed, succeeded bool
+
+	for _, word := range strings.Fields(strings.ToLower(message.Subject)) {
+		word = strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		switch word {
+		case "aborted", "error", "errors", "fail", "failed", "failure", "unable", "unsuccessful":
+			failed = true
+		case "complete", "completed", "done", "finished", "ready", "success", "successful", "successfully":
+			succeeded = true
+		}
+	}
+
+	switch {
+	case failed:
+		return &JobResult{
+			Finished: message.Timestamp,
+			Message:  strings.TrimSpace(message.Subject + ": " + message.Message),
+			Status:   JobStatusFailed,
+		}
+	case succeeded:
+		return &JobResult{
+			Finished: message.Timestamp,
+			Message:  message.Subject,
+			Status:   JobStatusSucceeded,
+		}
+	}
+
+	return nil
+}
+
+// newBackupJob returns a job that finishes when a backup that wasn't in the existing list appears, or when DA sends a
+// backup failure notification.
+func (c *UserContext) newBackupJob(domain string, existingBackups []Backup) *Job {
+	existing := make(map[string]bool, len(existingBackups))
+	for _, backup := range existingBackups {
+		existing[backup.Filename] = true
+	}
+
+	var job *Job
+
+	job = newJob(JobKindBackup, domain, func() (*JobResult, error) {
+		messages, err := c.GetMessages()
+		if err != nil {
+			return nil, err
+		}
+
+		if message := findJobMessage(messages, job.Started, domain, "backup"); message != nil {
+			if result := jobMessageResult(message); result != nil && result.Status == JobStatusFailed {
+				return result, nil
+			}
+		}
+
+		backups, err := c.GetBackups(domain)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, backup := range backups {
+			if !existing[backup.Filename] {
+				return &JobResult{
+					Message: "backup created",
+					Output:  backup.Filename,
+					Status:  JobStatusSucceeded,
+				}, nil
+			}
+		}
+
+		return nil, nil
+	})
+
+	return job
+}
+
+// newRestoreJob returns a job that finishes when DA sends a restore notification reporting success or failure.
+func (c *UserContext) newRestoreJob(domain string) *Job {
+	var job *Job
+
+	job = newJob(JobKindRestore, domain, func() (*JobResult, error) {
+		messages, err := c.GetMessages()
+		if err != nil {
+			return nil, err
+		}
+
+		if message := findJobMessage(messages, job.Started, domain, "restore"); message != nil {
+			return jobMessageResult(message), nil
+		}
+
+		return nil, nil
+	})
+
+	return job
+}
+
+// newSSLJob returns a job that finishes when a certificate with a different serial number covering all the given
+// hostnames is installed, or when DA sends a certificate failure notification.
+func (c *UserContext) newSSLJob(domain string, previousSerial string, hostnames []string) *Job {
+	var job *Job
+
+	job = newJob(JobKindSSL, domain, func() (*JobResult, error) {
+		messages, err := c.GetMessages()
+		if err != nil {
+			return nil, err
+		}
+
+		if message := findJobMessage(messages, job.Started, domain, "ssl", "certificate", "letsencrypt", "let's encrypt", "zerossl"); message != nil {
+			if result := jobMessageResult(message); result != nil && result.Status == JobStatusFailed {
+				return result, nil
+			}
+		}
+
+		certificate, err := c.getInstalledSSLCertificate(domain)
+		if err != nil {
+			return nil, err
+		}
+
+		if certificate == nil || certificate.SerialNumber == previousSerial {
+			// Either nothing is installed yet, or the old certificate is still in place.
+			return nil, nil
+		}
+
+		for _, hostname := range hostnames {
+			if !certificate.Covers(hostname) {
+				return nil, nil
+			}
+		}
+
+		return &JobResult{
+			Message: "certificate issued by " + certificate.Issuer,
+			Output:  certificate.SerialNumber,
+			Status:  JobStatusSucceeded,
+		}, nil
+	})
+
+	return job
+}
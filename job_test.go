@@ -0,0 +1,141 @@
+package directadmin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJobMessages(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	messages := []*Message{
+		{Subject: "Your backups are now ready", Message: "example.com", Timestamp: started.Add(-time.Hour)},
+		{Subject: "An error occurred during the backup", Message: "Disk quota exceeded for example.com.", Timestamp: started.Add(5 * time.Minute)},
+		{Subject: "Restore complete: example.com", Timestamp: started.Add(2 * time.Minute)},
+		// Newer notifications about other domains are ignored.
+		{Subject: "Backup of other.com failed", Timestamp: started.Add(6 * time.Minute)},
+		{Subject: "Restore of shop.example.com failed", Timestamp: started.Add(6 * time.Minute)},
+		{Subject: "Restore of example.com.au failed", Timestamp: started.Add(6 * time.Minute)},
+	}
+
+	message := findJobMessage(messages, started, "example.com", "backup")
+	if message != messages[1] {
+		t.Fatalf("expected the failed backup message, got %+v", message)
+	}
+
+	if result := jobMessageResult(message); result.Status != JobStatusFailed || result.Message != "An error occurred during the backup: Disk quota exceeded for example.com." {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if result := jobMessageResult(findJobMessage(messages, started, "example.com", "restore")); result.Status != JobStatusSucceeded {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if message = findJobMessage(messages, started, "example.com", "ssl"); message != nil {
+		t.Errorf("expected no ssl message, got %+v", message)
+	}
+
+	if message = findJobMessage(messages, started, "example.org", "backup"); message != nil {
+		t.Errorf("expected no message for example.org, got %+v", message)
+	}
+}
+
+func TestJobMessageResult(t *testing.T) {
+	tests := []struct {
+		expected JobStatus
+		message  Message
+	}{
+		{expected: JobStatusFailed, message: Message{Subject: "An error occurred during the backup"}},
+		{expected: JobStatusFailed, message: Message{Subject: "Restore FAILED: example.com"}},
+		{expected: JobStatusFailed, message: Message{Subject: "Unable to issue certificate, backup completed"}},
+		{expected: JobStatusSucceeded, message: Message{Subject: "Restore complete"}},
+		// Failure words inside domain names or the body don't fail the job.
+		{expected: JobStatusSucceeded, message: Message{Subject: "Backup of failover.tld completed"}},
+		{expected: JobStatusSucceeded, message: Message{Subject: "Restore of failed.example.com complete"}},
+		{expected: JobStatusSucceeded, message: Message{Subject: "Restore complete", Message: "0 errors, no failures"}},
+		// Without a known word, the outcome is unknown rather than a success.
+		{message: Message{Subject: "Restore of example.com", Message: "Disk quota exceeded"}},
+		{message: Message{Subject: "Backup of failover.tld"}},
+	}
+
+	for _, test := range tests {
+		result := jobMessageResult(&test.message)
+
+		if test.expected == "" {
+			if result != nil {
+				t.Errorf("%q: expected no result, got %+v", test.message.Subject, result)
+			}
+
+			continue
+		}
+
+		if result == nil || result.Status != test.expected {
+			t.Errorf("%q: expected %v, got %+v", test.message.Subject, test.expected, result)
+		}
+	}
+}
+
+func TestJobWait(t *testing.T) {
+	checks := 0
+	job := newJob(JobKindRestore, "example.com", func() (*JobResult, error) {
+		checks++
+		return &JobResult{Message: "Restore failed", Status: JobStatusFailed}, nil
+	})
+
+	result, err := job.Wait(context.Background())
+
+	var failedErr *JobFailedError
+	if !errors.As(err, &failedErr) || failedErr.Message != "Restore failed" {
+		t.Fatalf("expected a JobFailedError, got %v", err)
+	}
+
+	if again, _ := job.Poll(); again != result || checks != 1 {
+		t.Errorf("expected the finished result to be reused without checking again")
+	}
+}
+
+func TestSSLJobCertificateCheck(t *testing.T) {
+	var sslStatus int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/messages":
+			_, _ = w.Write([]byte("[]"))
+		case "/CMD_API_SSL":
+			w.WriteHeader(sslStatus)
+			_, _ = w.Write([]byte(`{"certificate":""}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	api, err := New(server.URL, time.Minute, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &UserContext{api: api, cookieJar: jar, credentials: credentials{username: "user", passkey: "passkey"}}
+
+	// No certificate yet means the job is still running.
+	sslStatus = http.StatusOK
+	if result, err := c.newSSLJob("example.com", "", []string{"example.com"}).Poll(); err != nil || result.Status != JobStatusRunning {
+		t.Errorf("expected the job to still be running, got %+v, %v", result, err)
+	}
+
+	// A failed lookup is reported rather than mistaken for a missing certificate.
+	sslStatus = http.StatusInternalServerError
+	if _, err := c.newSSLJob("example.com", "", []string{"example.com"}).Poll(); err == nil {
+		t.Error("expected the certificate lookup error to be returned")
+	}
+}
@@ -0,0 +1,220 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+type (
+	// ProtectedDirectory is a directory protected by HTTP basic auth. Paths are relative to the user's home directory,
+	// e.g. "/domains/example.com/public_html/admin".
+	ProtectedDirectory struct {
+		Path string `json:"path" yaml:"path"`
+		// Realm is the name shown in the browser's login prompt.
+		Realm string   `json:"realm" yaml:"realm"`
+		Users []string `json:"users" yaml:"users"`
+	}
+
+	rawProtectedDirectory struct {
+		Realm string `json:"name"`
+		Users string `json:"users"`
+	}
+)
+
+// AddProtectedDirectoryUser (user) adds a user who may access the given protected directory.
+func (c *UserContext) AddProtectedDirectoryUser(dirPath string, username string, password string) error {
+	directory, err := c.GetProtectedDirectory(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range directory.Users {
+		if user == username {
+			return fmt.Errorf("user %v already exists in %v", username, directory.Path)
+		}
+	}
+
+	return c.saveProtectedDirectoryUser(directory, username, password)
+}
+
+// DeleteProtectedDirectoryUsers (user) removes the given users from the given protected directory.
+func (c *UserContext) DeleteProtectedDirectoryUsers(dirPath string, usernames ...string) error {
+	var response apiGenericResponse
+
+	if len(usernames) == 0 {
+		return errors.New("no usernames provided")
+	}
+
+	body := url.Values{}
+	body.Set("delete", "yes")
+	body.Set("path", normalizeFilePath(dirPath))
+
+	for index, username := range usernames {
+		body.Set("select"+cast.ToString(index), username)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_FILE_MANAGER?action=protect", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Users Deleted" {
+		return fmt.Errorf("failed to delete protected directory users: %v", response.Result)
+	}
+
+	return nil
+}
+
+// GetProtectedDirectories (user) returns the session user's protected directories, sorted by path.
+func (c *UserContext) GetProtectedDirectories() ([]ProtectedDirectory, error) {
+	var rawDirectories map[string]rawProtectedDirectory
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_FILE_MANAGER?action=protected_directories", nil, &rawDirectories); err != nil {
+		return nil, err
+	}
+
+	directories := make([]ProtectedDirectory, 0, len(rawDirectories))
+
+	for dirPath, rawDirectory := range rawDirectories {
+		directories = append(directories, rawDirectory.translate(dirPath))
+	}
+
+	sort.Slice(directories, func(i, j int) bool {
+		return directories[i].Path < directories[j].Path
+	})
+
+	return directories, nil
+}
+
+// GetProtectedDirectory (user) returns the protected directory at the given path.
+func (c *UserContext) GetProtectedDirectory(dirPath string) (*ProtectedDirectory, error) {
+	dirPath = normalizeFilePath(dirPath)
+
+	directories, err := c.GetProtectedDirectories()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, directory := range directories {
+		if directory.Path == dirPath {
+			return &directory, nil
+		}
+	}
+
+	return nil, fmt.Errorf("directory is not protected: %v", dirPath)
+}
+
+// ProtectDirectory (user) enables HTTP basic auth on the given directory, or updates its realm if it's already
+// protected. Users must be added separately with AddProtectedDirectoryUser.
+func (c *UserContext) ProtectDirectory(dirPath string, realm string) error {
+	if realm == "" {
+		return errors.New("no realm provided")
+	}
+
+	return c.setDirectoryProtection(normalizeFilePath(dirPath), realm, true)
+}
+
+// UnprotectDirectory (user) removes HTTP basic auth from the given directory, including its users.
+func (c *UserContext) UnprotectDirectory(dirPath string) error {
+	dirPath, err := normalizeDestructivePath(dirPath)
+	if err != nil {
+		return err
+	}
+
+	return c.setDirectoryProtection(dirPath, "", false)
+}
+
+// UpdateProtectedDirectoryUserPassword (user) changes the password of an existing user in the given protected
+// directory.
+func (c *UserContext) UpdateProtectedDirectoryUserPassword(dirPath string, username string, password string) error {
+	directory, err := c.GetProtectedDirectory(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range directory.Users {
+		if user == username {
+			return c.saveProtectedDirectoryUser(directory, username, password)
+		}
+	}
+
+	return fmt.Errorf("user %v doesn't exist in %v", username, directory.Path)
+}
+
+// saveProtectedDirectoryUser creates the given user, or updates their password if they already exist.
+func (c *UserContext) saveProtectedDirectoryUser(directory *ProtectedDirectory, username string, password string) error {
+	var response apiGenericResponse
+
+	if username == "" || password == "" {
+		return errors.New("username and password are required")
+	}
+
+	if strings.Contains(username, ":") {
+		return fmt.Errorf("username can't contain a colon: %v", username)
+	}
+
+	body := url.Values{}
+	body.Set("enabled", "yes")
+	body.Set("name", directory.Realm)
+	body.Set("passwd", password)
+	body.Set("passwd2", password)
+	body.Set("path", directory.Path)
+	body.Set("user", username)
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_FILE_MANAGER?action=protect", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "Directory Protected" {
+		return fmt.Errorf("failed to save protected directory user: %v", response.Result)
+	}
+
+	return nil
+}
+
+func (c *UserContext) setDirectoryProtection(dirPath string, realm string, enabled bool) error {
+	var response apiGenericResponse
+
+	body := url.Values{}
+	body.Set("enabled", reverseParseYesNo(enabled))
+	body.Set("path", dirPath)
+
+	if enabled {
+		body.Set("name", realm)
+	}
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_FILE_MANAGER?action=protect", body, &response); err != nil {
+		return err
+	}
+
+	if enabled && response.Success != "Directory Protected" {
+		return fmt.Errorf("failed to protect directory: %v", response.Result)
+	} else if !enabled && response.Success != "Protection Removed" {
+		return fmt.Errorf("failed to unprotect directory: %v", response.Result)
+	}
+
+	return nil
+}
+
+func (d *rawProtectedDirectory) translate(dirPath string) ProtectedDirectory {
+	directory := ProtectedDirectory{
+		Path:  normalizeFilePath(dirPath),
+		Realm: d.Realm,
+		Users: []string{},
+	}
+
+	for _, user := range strings.Split(d.Users, ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			directory.Users = append(directory.Users, user)
+		}
+	}
+
+	sort.Strings(directory.Users)
+
+	return directory
+}
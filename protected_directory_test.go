@@ -0,0 +1,15 @@
+package directadmin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProtectedDirectoryTranslation(t *testing.T) {
+	raw := rawProtectedDirectory{Realm: "Admin Area", Users: "bob, alice,,"}
+	expected := ProtectedDirectory{Path: "/domains/example.com/public_html/admin", Realm: "Admin Area", Users: []string{"alice", "bob"}}
+
+	if directory := raw.translate("domains/example.com/public_html/admin/"); !reflect.DeepEqual(directory, expected) {
+		t.Errorf("expected %+v, got %+v", expected, directory)
+	}
+}
@@ -1,46 +1,396 @@
 package directadmin
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/spf13/cast"
 )
 
-// IssueSSL (user) requests a lets encrypt certificate for the given hostnames.
-func (c *UserContext) IssueSSL(domain string, hostnamesToCertify ...string) error {
-	var response apiGenericResponse
+const (
+	ACMEProviderLetsEncrypt = ACMEProvider("letsencrypt")
+	ACMEProviderZeroSSL     = ACMEProvider("zerossl")
+
+	SSLKeyTypeECDSAP256 = SSLKeyType("prime256v1")
+	SSLKeyTypeECDSAP384 = SSLKeyType("secp384r1")
+	SSLKeyTypeRSA2048   = SSLKeyType("2048")
+	SSLKeyTypeRSA4096   = SSLKeyType("4096")
+)
+
+type (
+	ACMEProvider string
+
+	// SSLKeyType is the key algorithm and size, using DA's keysize values.
+	SSLKeyType string
+
+	// SSLCertificate is a domain's installed certificate.
+	SSLCertificate struct {
+		// AutoRenew is set when DA will renew the certificate through ACME before it expires.
+		AutoRenew bool `json:"autoRenew"`
+		// CABundle is the chain of intermediate certificates, if one is installed.
+		CABundle []*x509.Certificate `json:"-"`
+		// Certificate is the parsed leaf certificate.
+		Certificate *x509.Certificate `json:"-"`
+		DNSNames    []string          `json:"dnsNames"`
+		Issuer      string            `json:"issuer"`
+		NotAfter    time.Time         `json:"notAfter"`
+		NotBefore   time.Time         `json:"notBefore"`
+		// PEM is the leaf certificate in PEM format.
+		PEM          string `json:"pem"`
+		SelfSigned   bool   `json:"selfSigned"`
+		SerialNumber string `json:"serialNumber"`
+		Subject      string `json:"subject"`
+	}
+
+	// SSLCertificateRequest holds the subject of a certificate signing request. Only CommonName is required.
+	SSLCertificateRequest struct {
+		City               string     `json:"city"`
+		CommonName         string     `json:"commonName"`
+		Country            string     `json:"country"`
+		Email              string     `json:"email"`
+		KeyType            SSLKeyType `json:"keyType"`
+		Organization       string     `json:"organization"`
+		OrganizationalUnit string     `json:"organizationalUnit"`
+		State              string     `json:"state"`
+	}
+
+	// SSLIssueOptions configures an ACME certificate request. Provider and KeyType default to Let's Encrypt and
+	// ECDSA P-384.
+	SSLIssueOptions struct {
+		Hostnames []string     `json:"hostnames"`
+		KeyType   SSLKeyType   `json:"keyType"`
+		Provider  ACMEProvider `json:"provider"`
+		// Wildcard requests a certificate for the domain and *.domain using DNS-01 validation, which requires the
+		// domain's DNS zone to be hosted on the server. Hostnames are ignored.
+		Wildcard bool `json:"wildcard"`
+	}
+
+	// SSLUpload is a certificate from any CA to install on a domain. All fields are PEM encoded.
+	SSLUpload struct {
+		CABundle    string `json:"caBundle"`
+		Certificate string `json:"certificate"`
+		PrivateKey  string `json:"privateKey"`
+	}
+)
+
+// Covers returns whether the certificate is valid for the given hostname, including through a wildcard name.
+func (s *SSLCertificate) Covers(hostname string) bool {
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+
+	for _, dnsName := range s.DNSNames {
+		dnsName = strings.ToLower(dnsName)
 
-	if len(hostnamesToCertify) == 0 {
+		if dnsName == hostname {
+			return true
+		}
+
+		// A wildcard only covers a single label, so *.example.com covers www.example.com but not a.b.example.com.
+		if suffix, ok := strings.CutPrefix(dnsName, "*."); ok {
+			if label, rest, found := strings.Cut(hostname, "."); found && label != "" && rest == suffix {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Validate checks the options locally.
+func (o *SSLIssueOptions) Validate() error {
+	if !o.Wildcard && len(o.Hostnames) == 0 {
 		return errors.New("at least one hostname is required for the certificate")
 	}
 
+	switch o.Provider {
+	case "", ACMEProviderLetsEncrypt, ACMEProviderZeroSSL:
+	default:
+		return fmt.Errorf("invalid ACME provider: %v", o.Provider)
+	}
+
+	return validateSSLKeyType(o.KeyType)
+}
+
+// Validate checks that the certificate and private key parse and belong together.
+func (u *SSLUpload) Validate() error {
+	if _, err := tls.X509KeyPair([]byte(u.Certificate), []byte(u.PrivateKey)); err != nil {
+		return fmt.Errorf("invalid certificate or private key: %w", err)
+	}
+
+	if strings.TrimSpace(u.CABundle) != "" {
+		if _, err := parsePEMCertificates(u.CABundle); err != nil {
+			return fmt.Errorf("invalid CA bundle: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GenerateCSR (user) generates a private key on the server for the given domain, and returns a certificate signing
+// request for it in PEM format. Upload the signed certificate with UploadSSL, without a private key.
+func (c *UserContext) GenerateCSR(domain string, csr SSLCertificateRequest) (string, error) {
+	var response struct {
+		apiGenericResponse
+		Request string `json:"request"`
+	}
+
+	if csr.CommonName == "" {
+		return "", errors.New("no common name provided")
+	}
+
+	if csr.KeyType == "" {
+		csr.KeyType = SSLKeyTypeRSA2048
+	}
+
+	if err := validateSSLKeyType(csr.KeyType); err != nil {
+		return "", err
+	}
+
+	body := url.Values{}
+	body.Set("action", "save")
+	body.Set("city", csr.City)
+	body.Set("company", csr.Organization)
+	body.Set("country", csr.Country)
+	body.Set("division", csr.OrganizationalUnit)
+	body.Set("domain", domain)
+	body.Set("email", csr.Email)
+	body.Set("encryption", "sha256")
+	body.Set("keysize", string(csr.KeyType))
+	body.Set("name", csr.CommonName)
+	body.Set("province", csr.State)
+	body.Set("type", "request")
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_SSL", body, &response); err != nil {
+		return "", err
+	}
+
+	block, _ := pem.Decode([]byte(response.Request))
+	if block == nil {
+		return "", fmt.Errorf("failed to generate CSR: %v", response.Result)
+	}
+
+	if _, err := x509.ParseCertificateRequest(block.Bytes); err != nil {
+		return "", fmt.Errorf("failed to parse generated CSR: %w", err)
+	}
+
+	return response.Request, nil
+}
+
+// GetSSLCertificate (user) returns the given domain's installed certificate.
+func (c *UserContext) GetSSLCertificate(domain string) (*SSLCertificate, error) {
+	certificate, err := c.getInstalledSSLCertificate(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if certificate == nil {
+		return nil, fmt.Errorf("no certificate is installed for %v", domain)
+	}
+
+	return certificate, nil
+}
+
+// IssueSSL (user) requests a lets encrypt certificate for the given hostnames. The returned job finishes once the new
+// certificate is installed.
+func (c *UserContext) IssueSSL(domain string, hostnamesToCertify ...string) (*Job, error) {
+	return c.IssueSSLWithOptions(domain, SSLIssueOptions{Hostnames: hostnamesToCertify})
+}
+
+// IssueSSLWithOptions (user) requests a certificate through ACME with the given options. The returned job finishes once
+// the new certificate is installed.
+func (c *UserContext) IssueSSLWithOptions(domain string, opts SSLIssueOptions) (*Job, error) {
+	var response apiGenericResponse
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opts.Provider == "" {
+		opts.Provider = ACMEProviderLetsEncrypt
+	}
+
+	if opts.KeyType == "" {
+		opts.KeyType = SSLKeyTypeECDSAP384
+	}
+
+	if opts.Wildcard {
+		// DA completes the DNS-01 challenge by adding a TXT record to the local zone.
+		if _, err := c.getRawDNSZone(domain); err != nil {
+			return nil, fmt.Errorf("wildcard certificates require the domain's DNS zone to be hosted on this server: %w", err)
+		}
+
+		opts.Hostnames = []string{domain, "*." + domain}
+	}
+
+	// The job detects the new certificate by its serial number changing.
+	var previousSerial string
+
+	certificate, err := c.getInstalledSSLCertificate(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current certificate: %w", err)
+	}
+
+	if certificate != nil {
+		previousSerial = certificate.SerialNumber
+	}
+
 	body := url.Values{
 		"type":          {"create"},
 		"request":       {"letsencrypt"},
-		"name":          {hostnamesToCertify[0]},
+		"name":          {opts.Hostnames[0]},
 		"domain":        {domain},
-		"keysize":       {"secp384r1"},
+		"keysize":       {string(opts.KeyType)},
 		"encryption":    {"sha256"},
-		"wildcard":      {"no"},
+		"wildcard":      {reverseParseYesNo(opts.Wildcard)},
 		"background":    {"auto"},
 		"action":        {"save"},
-		"acme_provider": {"letsencrypt"},
+		"acme_provider": {string(opts.Provider)},
 	}
 
-	for index, certDomain := range hostnamesToCertify {
+	for index, certDomain := range opts.Hostnames {
 		body.Set("le_select"+cast.ToString(index), certDomain)
 	}
 
+	if _, err = c.makeRequestOld(http.MethodPost, "API_SSL", body, &response); err != nil {
+		return nil, err
+	}
+
+	// With background=auto, DA either installs the certificate straight away or queues the request and says so.
+	if response.Success != "Certificate and Key Saved." && !sslRequestQueued(response) {
+		return nil, fmt.Errorf("failed to issue SSL certificate: %v", response.Result)
+	}
+
+	return c.newSSLJob(domain, previousSerial, opts.Hostnames), nil
+}
+
+// UploadSSL (user) installs the given certificate, private key and optional CA bundle on the domain. If the private
+// key is empty, the certificate must match the key generated by GenerateCSR.
+func (c *UserContext) UploadSSL(domain string, upload SSLUpload) error {
+	var response apiGenericResponse
+
+	if upload.PrivateKey != "" {
+		if err := upload.Validate(); err != nil {
+			return err
+		}
+	} else if _, err := parsePEMCertificates(upload.Certificate); err != nil {
+		return fmt.Errorf("invalid certificate: %w", err)
+	}
+
+	body := url.Values{}
+	body.Set("action", "save")
+	body.Set("domain", domain)
+	body.Set("type", "paste")
+
+	// DA expects the key and certificate in a single field.
+	body.Set("certificate", strings.TrimSpace(upload.PrivateKey+"\n"+upload.Certificate))
+
 	if _, err := c.makeRequestOld(http.MethodPost, "API_SSL", body, &response); err != nil {
 		return err
 	}
 
 	if response.Success != "Certificate and Key Saved." {
-		return fmt.Errorf("failed to issue SSL certificate: %v", response.Result)
+		return fmt.Errorf("failed to upload SSL certificate: %v", response.Result)
+	}
+
+	if strings.TrimSpace(upload.CABundle) == "" {
+		return nil
+	}
+
+	body = url.Values{}
+	body.Set("action", "save")
+	body.Set("active", "yes")
+	body.Set("cacert", upload.CABundle)
+	body.Set("domain", domain)
+	body.Set("type", "cacert")
+
+	if _, err := c.makeRequestOld(http.MethodPost, "API_SSL", body, &response); err != nil {
+		return err
+	}
+
+	if response.Success != "CA Certificate Saved" {
+		return fmt.Errorf("certificate was uploaded, but failed to save CA bundle: %v", response.Result)
 	}
 
 	return nil
 }
+
+// getInstalledSSLCertificate (user) returns the given domain's installed certificate, or nil if none is installed.
+func (c *UserContext) getInstalledSSLCertificate(domain string) (*SSLCertificate, error) {
+	rawCertificate, err := c.getRawSSLCertificate(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(rawCertificate.Certificate) == "" {
+		return nil, nil
+	}
+
+	return rawCertificate.translate()
+}
+
+// getRawSSLCertificate (user) returns DA's SSL response for the given domain, which has an empty certificate if none
+// is installed.
+func (c *UserContext) getRawSSLCertificate(domain string) (*rawSSLCertificate, error) {
+	var rawCertificate rawSSLCertificate
+
+	if _, err := c.makeRequestOld(http.MethodGet, "API_SSL?domain="+domain, nil, &rawCertificate); err != nil {
+		return nil, err
+	}
+
+	return &rawCertificate, nil
+}
+
+// parsePEMCertificates parses every certificate in the given PEM text, ignoring any other blocks such as keys.
+func parsePEMCertificates(pemText string) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+
+	rest := []byte(pemText)
+
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, errors.New("no PEM certificates found")
+	}
+
+	return certificates, nil
+}
+
+func validateSSLKeyType(keyType SSLKeyType) error {
+	switch keyType {
+	case "", SSLKeyTypeECDSAP256, SSLKeyTypeECDSAP384, SSLKeyTypeRSA2048, SSLKeyTypeRSA4096:
+		return nil
+	}
+
+	return fmt.Errorf("invalid key type: %v", keyType)
+}
+
+// sslRequestQueued reports whether DA's response to a certificate request says it was sent to the background.
+func sslRequestQueued(response apiGenericResponse) bool {
+	text := strings.ToLower(response.Success + " " + response.Result)
+
+	return strings.Contains(text, "background") || strings.Contains(text, "queued")
+}
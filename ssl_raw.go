@@ -0,0 +1,56 @@
+package directadmin
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+type rawSSLCertificate struct {
+	AutoRenew   string `json:"le_auto_renew"`
+	CABundle    string `json:"cacert"`
+	Certificate string `json:"certificate"`
+}
+
+// translate parses the certificate PEM data into an SSLCertificate object.
+func (r *rawSSLCertificate) translate() (*SSLCertificate, error) {
+	certificates, err := parsePEMCertificates(r.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	leaf := certificates[0]
+
+	certificate := &SSLCertificate{
+		AutoRenew:    parseOnOff(r.AutoRenew),
+		Certificate:  leaf,
+		DNSNames:     leaf.DNSNames,
+		Issuer:       leaf.Issuer.String(),
+		NotAfter:     leaf.NotAfter,
+		NotBefore:    leaf.NotBefore,
+		PEM:          string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})),
+		SelfSigned:   bytes.Equal(leaf.RawIssuer, leaf.RawSubject) && leaf.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature) == nil,
+		SerialNumber: leaf.SerialNumber.Text(16),
+		Subject:      leaf.Subject.String(),
+	}
+
+	// Some certificates only name their host in the subject.
+	if len(certificate.DNSNames) == 0 && leaf.Subject.CommonName != "" {
+		certificate.DNSNames = []string{leaf.Subject.CommonName}
+	}
+
+	// DA sometimes stores the chain alongside the leaf, rather than as a separate CA bundle.
+	certificate.CABundle = certificates[1:]
+
+	if strings.TrimSpace(r.CABundle) != "" {
+		caBundle, err := parsePEMCertificates(r.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CA bundle: %w", err)
+		}
+
+		certificate.CABundle = append(certificate.CABundle, caBundle...)
+	}
+
+	return certificate, nil
+}
@@ -0,0 +1,240 @@
+package directadmin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	SSLProblemExpired      = SSLProblemType("expired")
+	SSLProblemExpiring     = SSLProblemType("expiring")
+	SSLProblemHostMismatch = SSLProblemType("hostMismatch")
+	SSLProblemMissing      = SSLProblemType("missing")
+	SSLProblemSelfSigned   = SSLProblemType("selfSigned")
+)
+
+type (
+	SSLProblemType string
+
+	SSLProblem struct {
+		Message string         `json:"message"`
+		Type    SSLProblemType `json:"type"`
+	}
+
+	// SSLReportEntry describes the certificate for a single domain, which also serves its subdomains.
+	SSLReportEntry struct {
+		AutoRenew bool `json:"autoRenew"`
+		// CoveredHosts are the names the certificate is issued for, which may include wildcards.
+		CoveredHosts []string `json:"coveredHosts"`
+		Domain       string   `json:"domain"`
+		// ExpectedHosts are the domain, its www host, and its subdomains.
+		ExpectedHosts []string  `json:"expectedHosts"`
+		Expires       time.Time `json:"expires"`
+		Issuer        string    `json:"issuer"`
+		// MissingHosts are the expected hosts the certificate doesn't cover.
+		MissingHosts []string     `json:"missingHosts"`
+		Problems     []SSLProblem `json:"problems"`
+		Username     string       `json:"username"`
+	}
+
+	SSLReportOptions struct {
+		// Concurrency is how many users are checked at once. Defaults to 5.
+		Concurrency int
+		// ExpiryWarning is how far ahead of expiry certificates are flagged. Defaults to 14 days.
+		ExpiryWarning time.Duration
+	}
+)
+
+// OK returns whether no problems were found.
+func (e *SSLReportEntry) OK() bool {
+	return len(e.Problems) == 0
+}
+
+// SSLReport (reseller) checks the certificate of every domain belonging to the session user's users, sorted by username
+// and domain. If some users or domains couldn't be checked, the entries that could be are returned along with an
+// error.
+func (c *ResellerContext) SSLReport(opts SSLReportOptions) ([]SSLReportEntry, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 5
+	}
+
+	if opts.ExpiryWarning <= 0 {
+		opts.ExpiryWarning = 14 * 24 * time.Hour
+	}
+
+	users, err := c.GetMyUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	var entries []SSLReportEntry
+	var errs []error
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wg.Add(len(users))
+
+	semaphore := make(chan struct{}, opts.Concurrency)
+	now := time.Now()
+
+	for _, user := range users {
+		go func(username string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			userEntries, userErrs := c.userSSLReport(username, now, opts.ExpiryWarning)
+
+			mu.Lock()
+			entries = append(entries, userEntries...)
+			errs = append(errs, userErrs...)
+			mu.Unlock()
+		}(user.Username)
+	}
+
+	wg.Wait()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Username != entries[j].Username {
+			return entries[i].Username < entries[j].Username
+		}
+
+		return entries[i].Domain < entries[j].Domain
+	})
+
+	if len(errs) > 0 {
+		counter := 0
+		var errStrings []string
+
+		for _, err := range errs {
+			counter++
+			errStrings = append(errStrings, "error "+cast.ToString(counter)+": "+err.Error())
+		}
+
+		return entries, errors.New(strings.Join(errStrings, "; "))
+	}
+
+	return entries, nil
+}
+
+// userSSLReport checks each of the given user's domains in turn.
+func (c *ResellerContext) userSSLReport(username string, now time.Time, expiryWarning time.Duration) ([]SSLReportEntry, []error) {
+	var entries []SSLReportEntry
+	var errs []error
+
+	userCtx, err := c.LoginAsMyUser(username)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to log in as %v: %w", username, err)}
+	}
+
+	domains, err := userCtx.ListDomains()
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list domains for %v: %w", username, err)}
+	}
+
+	for _, domain := range domains {
+		subdomains, err := userCtx.ListSubdomains(domain)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list subdomains for %v: %w", domain, err))
+			continue
+		}
+
+		rawCertificate, err := userCtx.getRawSSLCertificate(domain)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get certificate for %v: %w", domain, err))
+			continue
+		}
+
+		var certificate *SSLCertificate
+
+		if strings.TrimSpace(rawCertificate.Certificate) != "" {
+			if certificate, err = rawCertificate.translate(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to parse certificate for %v: %w", domain, err))
+				continue
+			}
+		}
+
+		entry := checkSSLCertificate(domain, subdomains, certificate, now, expiryWarning)
+		entry.Username = username
+
+		entries = append(entries, entry)
+	}
+
+	return entries, errs
+}
+
+// checkSSLCertificate builds the report entry for a domain's certificate, which is nil if none is installed.
+func checkSSLCertificate(domain string, subdomains []string, certificate *SSLCertificate, now time.Time, expiryWarning time.Duration) SSLReportEntry {
+	entry := SSLReportEntry{
+		CoveredHosts:  []string{},
+		Domain:        domain,
+		ExpectedHosts: []string{domain, "www." + domain},
+		MissingHosts:  []string{},
+		Problems:      []SSLProblem{},
+	}
+
+	for _, subdomain := range subdomains {
+		entry.ExpectedHosts = append(entry.ExpectedHosts, subdomain+"."+domain)
+	}
+
+	if certificate == nil {
+		entry.MissingHosts = entry.ExpectedHosts
+		entry.Problems = append(entry.Problems, SSLProblem{
+			Message: "no certificate is installed",
+			Type:    SSLProblemMissing,
+		})
+
+		return entry
+	}
+
+	entry.AutoRenew = certificate.AutoRenew
+	entry.CoveredHosts = certificate.DNSNames
+	entry.Expires = certificate.NotAfter
+	entry.Issuer = certificate.Issuer
+
+	for _, host := range entry.ExpectedHosts {
+		if !certificate.Covers(host) {
+			entry.MissingHosts = append(entry.MissingHosts, host)
+		}
+	}
+
+	switch {
+	case !now.Before(certificate.NotAfter):
+		entry.Problems = append(entry.Problems, SSLProblem{
+			Message: "certificate expired on " + certificate.NotAfter.Format(time.DateOnly),
+			Type:    SSLProblemExpired,
+		})
+	case certificate.NotAfter.Sub(now) < expiryWarning:
+		message := "certificate expires on " + certificate.NotAfter.Format(time.DateOnly)
+		if !certificate.AutoRenew {
+			message += " and auto-renew is disabled"
+		}
+
+		entry.Problems = append(entry.Problems, SSLProblem{
+			Message: message,
+			Type:    SSLProblemExpiring,
+		})
+	}
+
+	if len(entry.MissingHosts) > 0 {
+		entry.Problems = append(entry.Problems, SSLProblem{
+			Message: "certificate doesn't cover " + strings.Join(entry.MissingHosts, ", "),
+			Type:    SSLProblemHostMismatch,
+		})
+	}
+
+	if certificate.SelfSigned {
+		entry.Problems = append(entry.Problems, SSLProblem{
+			Message: "certificate is self-signed",
+			Type:    SSLProblemSelfSigned,
+		})
+	}
+
+	return entry
+}
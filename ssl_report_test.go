@@ -0,0 +1,47 @@
+package directadmin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckSSLCertificate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	warning := 14 * 24 * time.Hour
+
+	certificate := &SSLCertificate{
+		AutoRenew: true,
+		DNSNames:  []string{"example.com", "*.example.com"},
+		NotAfter:  now.Add(60 * 24 * time.Hour),
+	}
+
+	entry := checkSSLCertificate("example.com", []string{"shop", "a.b"}, certificate, now, warning)
+	if !reflect.DeepEqual(entry.MissingHosts, []string{"a.b.example.com"}) {
+		t.Errorf("unexpected missing hosts: %v", entry.MissingHosts)
+	}
+
+	if len(entry.Problems) != 1 || entry.Problems[0].Type != SSLProblemHostMismatch {
+		t.Errorf("expected a host mismatch, got %+v", entry.Problems)
+	}
+
+	certificate.AutoRenew = false
+	certificate.NotAfter = now.Add(3 * 24 * time.Hour)
+
+	entry = checkSSLCertificate("example.com", nil, certificate, now, warning)
+	if len(entry.Problems) != 1 || entry.Problems[0].Type != SSLProblemExpiring {
+		t.Errorf("expected an expiring certificate, got %+v", entry.Problems)
+	}
+
+	certificate.NotAfter = now.Add(-time.Hour)
+
+	entry = checkSSLCertificate("example.com", nil, certificate, now, warning)
+	if len(entry.Problems) != 1 || entry.Problems[0].Type != SSLProblemExpired {
+		t.Errorf("expected an expired certificate, got %+v", entry.Problems)
+	}
+
+	entry = checkSSLCertificate("example.com", nil, nil, now, warning)
+	if entry.OK() || entry.Problems[0].Type != SSLProblemMissing {
+		t.Errorf("expected a missing certificate, got %+v", entry.Problems)
+	}
+}
@@ -0,0 +1,96 @@
+package directadmin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testSSLCertificate returns a self-signed certificate and its private key in PEM format.
+func testSSLCertificate(t *testing.T, notAfter time.Time, dnsNames ...string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		DNSNames:     dnsNames,
+		NotAfter:     notAfter,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+	}
+
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestSSLCertificateTranslation(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	certificatePEM, keyPEM := testSSLCertificate(t, notAfter, "example.com", "www.example.com")
+
+	rawCertificate := rawSSLCertificate{AutoRenew: "yes", Certificate: keyPEM + certificatePEM}
+
+	certificate, err := rawCertificate.translate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(certificate.DNSNames, []string{"example.com", "www.example.com"}) {
+		t.Errorf("unexpected DNS names: %v", certificate.DNSNames)
+	}
+
+	if !certificate.NotAfter.Equal(notAfter) || !certificate.AutoRenew || !certificate.SelfSigned {
+		t.Errorf("unexpected certificate details: %+v", certificate)
+	}
+
+	if certificate.Subject != "CN=example.com" || certificate.SerialNumber != "2a" || len(certificate.CABundle) != 0 {
+		t.Errorf("unexpected certificate details: %+v", certificate)
+	}
+
+	upload := SSLUpload{Certificate: certificatePEM, PrivateKey: keyPEM}
+	if err = upload.Validate(); err != nil {
+		t.Errorf("expected matching certificate and key to validate: %v", err)
+	}
+
+	_, otherKeyPEM := testSSLCertificate(t, notAfter, "example.com")
+
+	upload.PrivateKey = otherKeyPEM
+	if err = upload.Validate(); err == nil {
+		t.Error("expected mismatched certificate and key to fail validation")
+	}
+}
+
+func TestSSLRequestQueued(t *testing.T) {
+	tests := map[apiGenericResponse]bool{
+		{Success: "Certificate and Key Saved."}:                                   false,
+		{Success: "Request sent to the background", Result: "You'll be notified"}: true,
+		{Result: "The certificate request has been queued"}:                       true,
+		{Result: "Invalid domain"}:                                                false,
+	}
+
+	for response, expected := range tests {
+		if queued := sslRequestQueued(response); queued != expected {
+			t.Errorf("%+v: expected queued to be %v, got %v", response, expected, queued)
+		}
+	}
+}
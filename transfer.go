@@ -0,0 +1,112 @@
+package directadmin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+)
+
+// ProgressFunc is called as a transfer progresses, with the bytes transferred so far and the total size. The total is
+// -1 if it isn't known.
+type ProgressFunc func(transferred int64, total int64)
+
+type (
+	progressReader struct {
+		progress    ProgressFunc
+		reader      io.Reader
+		total       int64
+		transferred int64
+	}
+
+	progressWriter struct {
+		progress    ProgressFunc
+		total       int64
+		transferred int64
+		writer      io.Writer
+	}
+)
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.transferred += int64(n)
+		r.progress(r.transferred, r.total)
+	}
+
+	return n, err
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	if n > 0 {
+		w.transferred += int64(n)
+		w.progress(w.transferred, w.total)
+	}
+
+	return n, err
+}
+
+// withProgressReader wraps the reader to report progress, if a progress function is given.
+func withProgressReader(reader io.Reader, total int64, progress ProgressFunc) io.Reader {
+	if progress == nil {
+		return reader
+	}
+
+	return &progressReader{progress: progress, reader: reader, total: total}
+}
+
+// withProgressWriter wraps the writer to report progress, if a progress function is given.
+func withProgressWriter(writer io.Writer, total int64, progress ProgressFunc) io.Writer {
+	if progress == nil {
+		return writer
+	}
+
+	return &progressWriter{progress: progress, total: total, writer: writer}
+}
+
+// pipeMultipart returns a multipart body containing the reader's contents as a single file field, written through a
+// pipe as the body is read, so the file is never held in memory. If size isn't -1, the body fails unless exactly size
+// bytes are read, and the body's total length is returned so it can be sent with a Content-Length. Otherwise the
+// length is -1.
+func pipeMultipart(fieldName string, fileName string, reader io.Reader, size int64) (*io.PipeReader, string, int64) {
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+
+	contentLength := int64(-1)
+
+	if size != -1 {
+		// The headers and closing boundary don't depend on the file data, so write them without it to measure them.
+		var framing bytes.Buffer
+
+		framingWriter := multipart.NewWriter(&framing)
+		if err := framingWriter.SetBoundary(writer.Boundary()); err == nil {
+			if _, err = framingWriter.CreateFormFile(fieldName, fileName); err == nil && framingWriter.Close() == nil {
+				contentLength = int64(framing.Len()) + size
+			}
+		}
+	}
+
+	go func() {
+		part, err := writer.CreateFormFile(fieldName, fileName)
+		if err != nil {
+			pipeWriter.CloseWithError(fmt.Errorf("creating form file: %w", err))
+			return
+		}
+
+		written, err := io.Copy(part, reader)
+		if err != nil {
+			pipeWriter.CloseWithError(fmt.Errorf("writing file data: %w", err))
+			return
+		}
+
+		if size != -1 && written != size {
+			pipeWriter.CloseWithError(fmt.Errorf("expected %d bytes of file data, but read %d", size, written))
+			return
+		}
+
+		pipeWriter.CloseWithError(writer.Close())
+	}()
+
+	return pipeReader, writer.FormDataContentType(), contentLength
+}
@@ -0,0 +1,70 @@
+package directadmin
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestPipeMultipart(t *testing.T) {
+	const fileData = "SELECT 1;\n"
+
+	var lastTransferred, lastTotal int64
+
+	progress := func(transferred int64, total int64) {
+		lastTransferred, lastTotal = transferred, total
+	}
+
+	body, contentType, contentLength := pipeMultipart("sqlfile", "dump.sql", withProgressReader(strings.NewReader(fileData), int64(len(fileData)), progress), int64(len(fileData)))
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bodyData, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if int64(len(bodyData)) != contentLength {
+		t.Errorf("expected a content length of %d, got %d", len(bodyData), contentLength)
+	}
+
+	part, err := multipart.NewReader(bytes.NewReader(bodyData), params["boundary"]).NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if part.FormName() != "sqlfile" || part.FileName() != "dump.sql" {
+		t.Errorf("unexpected part %q with filename %q", part.FormName(), part.FileName())
+	}
+
+	data, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != fileData {
+		t.Errorf("expected %q, got %q", fileData, data)
+	}
+
+	if lastTransferred != int64(len(fileData)) || lastTotal != int64(len(fileData)) {
+		t.Errorf("expected progress %d/%d, got %d/%d", len(fileData), len(fileData), lastTransferred, lastTotal)
+	}
+
+	// A reader that ends early must fail the body rather than upload a truncated file.
+	body, _, _ = pipeMultipart("file", "short.txt", strings.NewReader("abc"), 10)
+	if _, err = io.ReadAll(body); err == nil {
+		t.Error("expected a size mismatch to fail the body")
+	}
+
+	// Without a size, the length isn't known and the body is sent chunked.
+	body, _, contentLength = pipeMultipart("file", "unknown.txt", strings.NewReader("abc"), -1)
+	if _, err = io.ReadAll(body); err != nil || contentLength != -1 {
+		t.Errorf("expected an unknown content length, got %d, %v", contentLength, err)
+	}
+}